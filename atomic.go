@@ -0,0 +1,53 @@
+package ectoenv
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// isAtomicType reports whether t is one of the types from sync/atomic, such as atomic.Int64 or atomic.Bool
+func isAtomicType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == "sync/atomic"
+}
+
+// setAtomicField parses the value according to the argument of the field's Store method and stores it atomically, so
+// that readers can Load the field concurrently with a refresh. The argument is parsed like a field of the same kind,
+// honoring the bool mode, digit separators and `env-base` tag. An empty value stores the zero value.
+func setAtomicField(field reflect.Value, structField reflect.StructField, opts parseOptions, envValue string) error {
+	store := field.Addr().MethodByName("Store")
+	if !store.IsValid() || store.Type().NumIn() != 1 {
+		return fmt.Errorf("unable to set value for field %s. unsupported atomic type %s", structField.Name, field.Type())
+	}
+
+	argType := store.Type().In(0)
+	arg := reflect.New(argType).Elem()
+	if envValue != "" {
+		if opts.digitSeparators != "" && isNumericKind(argType.Kind()) {
+			envValue = stripDigitSeparators(envValue, opts.digitSeparators)
+		}
+
+		var err error
+		switch argType.Kind() {
+		case reflect.Int32, reflect.Int64:
+			var base int
+			if base, err = getBase(structField); err == nil {
+				err = setIntField(arg, structField.Name, base, envValue)
+			}
+		case reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			var base int
+			if base, err = getBase(structField); err == nil {
+				err = setUintField(arg, structField.Name, base, envValue)
+			}
+		case reflect.Bool:
+			err = setBoolField(arg, structField.Name, opts.boolMode, envValue)
+		default:
+			err = fmt.Errorf("unable to set value for field %s. unsupported atomic type %s", structField.Name, field.Type())
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	store.Call([]reflect.Value{arg})
+	return nil
+}
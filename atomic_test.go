@@ -0,0 +1,101 @@
+package ectoenv
+
+import (
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBindEnvAtomic(t *testing.T) {
+	type Config struct {
+		Int32  atomic.Int32  `env:"TEST_ATOMIC_INT32"`
+		Int64  atomic.Int64  `env:"TEST_ATOMIC_INT64"`
+		Uint32 atomic.Uint32 `env:"TEST_ATOMIC_UINT32"`
+		Uint64 atomic.Uint64 `env:"TEST_ATOMIC_UINT64" env-default:"7"`
+		Bool   atomic.Bool   `env:"TEST_ATOMIC_BOOL"`
+	}
+
+	envVars := map[string]string{
+		"TEST_ATOMIC_INT32":  "-32",
+		"TEST_ATOMIC_INT64":  "64",
+		"TEST_ATOMIC_UINT32": "32",
+		"TEST_ATOMIC_BOOL":   "true",
+	}
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	var config Config
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+
+	if got := config.Int32.Load(); got != -32 {
+		t.Errorf("Int32 got = %v, want %v", got, -32)
+	}
+	if got := config.Int64.Load(); got != 64 {
+		t.Errorf("Int64 got = %v, want %v", got, 64)
+	}
+	if got := config.Uint32.Load(); got != 32 {
+		t.Errorf("Uint32 got = %v, want %v", got, 32)
+	}
+	if got := config.Uint64.Load(); got != 7 {
+		t.Errorf("Uint64 got = %v, want %v", got, 7)
+	}
+	if got := config.Bool.Load(); !got {
+		t.Errorf("Bool got = %v, want %v", got, true)
+	}
+
+	os.Setenv("TEST_ATOMIC_INT32", "3000000000")
+	if err := BindEnv(&config); err == nil {
+		t.Errorf("BindEnv() expected error for int32 overflow, got nil")
+	}
+}
+
+func TestBindEnvAtomicParseOptions(t *testing.T) {
+	type Config struct {
+		Mask    atomic.Uint32 `env:"MASK" env-base:"16"`
+		Limit   atomic.Int64  `env:"LIMIT"`
+		Enabled atomic.Bool   `env:"ENABLED"`
+		Debug   atomic.Bool   `env:"DEBUG" env-bool-mode:"numeric"`
+	}
+
+	envVars := map[string]string{"MASK": "ff", "LIMIT": "1_000_000", "ENABLED": "yes", "DEBUG": "2"}
+
+	var config Config
+	if err := BindEnvWith(&config, withLookupMap(envVars), WithExtendedBools(true), WithUnderscoreDigits(true)); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+
+	if got := config.Mask.Load(); got != 0xff {
+		t.Errorf("Mask got = %v, want %v", got, 0xff)
+	}
+	if got := config.Limit.Load(); got != 1000000 {
+		t.Errorf("Limit got = %v, want %v", got, 1000000)
+	}
+	if got := config.Enabled.Load(); !got {
+		t.Errorf("Enabled got = %v, want %v", got, true)
+	}
+	if got := config.Debug.Load(); !got {
+		t.Errorf("Debug got = %v, want %v", got, true)
+	}
+}
+
+func TestBindEnvUnsupportedAtomic(t *testing.T) {
+	type Config struct {
+		Value atomic.Value `env:"TEST_ATOMIC_VALUE"`
+	}
+
+	os.Setenv("TEST_ATOMIC_VALUE", "value")
+	defer os.Unsetenv("TEST_ATOMIC_VALUE")
+
+	var config Config
+	if err := BindEnv(&config); err == nil {
+		t.Errorf("BindEnv() expected error for unsupported atomic type, got nil")
+	}
+}
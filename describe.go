@@ -0,0 +1,106 @@
+package ectoenv
+
+import (
+	"reflect"
+	"strings"
+)
+
+// VarDoc documents a variable that a struct is bound from
+type VarDoc struct {
+	// Key is the name of the variable. Variables of the elements of slices and maps of structs use the placeholders
+	// <index> and <name>, such as UPSTREAM_<index>_URL.
+	Key string
+	// Aliases are the fallback names of the variable, tried in order when Key is unset
+	Aliases []string
+	// Field is the path of the field from the root struct, such as "Database.URL"
+	Field string
+	// Type is the Go type of the field
+	Type string
+	// Default is the value of the `env-default` tag, or empty for secrets
+	Default string
+	// Required reports whether the variable must be set
+	Required bool
+	// RequiredIf is the name of the bool field that makes the variable required when it is true
+	RequiredIf string
+	// OneOf lists the values allowed by the `env-oneof` tag
+	OneOf []string
+	// Min and Max are the bounds set by the `env-min` and `env-max` tags
+	Min, Max string
+	// Secret reports whether the field is marked with `env-secret`
+	Secret bool
+}
+
+// Describe lists every variable the provided struct is bound from, in field order, for generating documentation or
+// --help-env output. Nested structs are walked with their prefixes applied. Defaults of fields marked with
+// `env-secret:"true"` are omitted.
+// v: a struct or a pointer to a struct
+// returns: the documentation of each variable, or nil if v is not a struct
+func Describe(v interface{}) []VarDoc {
+	rt := reflect.TypeOf(v)
+	if rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return newBinder().describeFields(rt, "", "")
+}
+
+func (b *binder) describeFields(rt reflect.Type, prefix, path string) []VarDoc {
+	var docs []VarDoc
+	for i := 0; i < rt.NumField(); i++ {
+		structField := b.field(rt, i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		if isNestedStruct(structField.Type) {
+			docs = append(docs, b.describeFields(structField.Type, b.nestedPrefix(structField, prefix), path+structField.Name+".")...)
+			continue
+		}
+		if embedded, ok := embeddedStructType(structField); ok {
+			docs = append(docs, b.describeFields(embedded, b.nestedPrefix(structField, prefix), path+structField.Name+".")...)
+			continue
+		}
+
+		names := b.envNames(structField)
+		if len(names) == 0 {
+			continue
+		}
+
+		switch {
+		case isStructSlice(structField.Type):
+			docs = append(docs, b.describeFields(structElemType(structField.Type), prefix+names[0]+"_<index>_", path+structField.Name+"[].")...)
+			continue
+		case isStructMap(structField.Type):
+			docs = append(docs, b.describeFields(structElemType(structField.Type), prefix+names[0]+"_<name>_", path+structField.Name+"[].")...)
+			continue
+		}
+
+		doc := VarDoc{
+			Key:        prefix + names[0],
+			Field:      path + structField.Name,
+			Type:       structField.Type.String(),
+			Required:   getTag(structField, ENV_REQUIRED_TAG) == "true",
+			RequiredIf: getTag(structField, ENV_REQUIRED_IF_TAG),
+			Min:        getTag(structField, ENV_MIN_TAG),
+			Max:        getTag(structField, ENV_MAX_TAG),
+			Secret:     getTag(structField, ENV_SECRET_TAG) == "true",
+		}
+		if isIndexedSlice(structField) {
+			doc.Key = prefix + names[0] + "_<index>"
+		}
+		for _, alias := range names[1:] {
+			doc.Aliases = append(doc.Aliases, prefix+alias)
+		}
+		if !doc.Secret {
+			doc.Default = getTag(structField, canonicalDefaultTag)
+		}
+		if oneOf := getTag(structField, ENV_ONEOF_TAG); oneOf != "" {
+			doc.OneOf = strings.Split(oneOf, ",")
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
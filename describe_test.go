@@ -0,0 +1,42 @@
+package ectoenv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDescribe(t *testing.T) {
+	type Upstream struct {
+		URL string `env:"URL" env-required:"true"`
+	}
+	type Database struct {
+		URL      string `env:"URL,DSN"`
+		Password string `env:"PASSWORD" env-default:"hunter2" env-secret:"true"`
+	}
+	type Config struct {
+		Level     string        `env:"LOG_LEVEL" env-default:"info" env-oneof:"debug,info,error"`
+		Timeout   time.Duration `env:"TIMEOUT,min=1s,max=1m"`
+		Database  Database      `env-prefix:"DB_"`
+		Upstreams []Upstream    `env:"UPSTREAM"`
+		Untagged  string
+	}
+
+	expected := []VarDoc{
+		{Key: "LOG_LEVEL", Field: "Level", Type: "string", Default: "info", OneOf: []string{"debug", "info", "error"}},
+		{Key: "TIMEOUT", Field: "Timeout", Type: "time.Duration", Min: "1s", Max: "1m"},
+		{Key: "DB_URL", Aliases: []string{"DB_DSN"}, Field: "Database.URL", Type: "string"},
+		{Key: "DB_PASSWORD", Field: "Database.Password", Type: "string", Secret: true},
+		{Key: "UPSTREAM_<index>_URL", Field: "Upstreams[].URL", Type: "string", Required: true},
+	}
+
+	if docs := Describe(&Config{}); !reflect.DeepEqual(docs, expected) {
+		t.Errorf("Describe() got = %+v, want %+v", docs, expected)
+	}
+	if docs := Describe(Config{}); !reflect.DeepEqual(docs, expected) {
+		t.Errorf("Describe() got = %+v for a struct value, want %+v", docs, expected)
+	}
+	if docs := Describe(42); docs != nil {
+		t.Errorf("Describe() got = %+v for a non-struct, want nil", docs)
+	}
+}
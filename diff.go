@@ -0,0 +1,90 @@
+package ectoenv
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldChange describes a field whose value differs between two structs
+type FieldChange struct {
+	// Name is the path of the field from the root struct, such as "Database.URL" or "Upstreams[1].URL"
+	Name string
+	// Key is the environment variable the field is bound from
+	Key string
+	// Old is the string representation of the field in the old struct, or empty if it only exists in the new one
+	Old string
+	// New is the string representation of the field in the new struct, or empty if it only exists in the old one
+	New string
+}
+
+// DiffEnv compares two structs of the same type and returns the fields whose values differ. Values are formatted as
+// they are by MarshalEnv and map entries are compared in sorted order. Fields marked with `env-secret:"true"` are
+// compared by their actual values, so a rotated secret is reported, but both sides of the change read REDACTED_VALUE.
+// Changes are returned in field order, followed by any fields that only exist in the new struct, such as elements
+// appended to a slice of structs.
+// old: a struct or a non-nil pointer to a struct
+// new: a struct or a non-nil pointer to a struct of the same type as old
+// returns: the changed fields or an error if the values are not structs of the same type
+func DiffEnv(old, new interface{}) ([]FieldChange, error) {
+	oldV := reflect.ValueOf(old)
+	if oldV.Kind() == reflect.Ptr && !oldV.IsNil() {
+		oldV = oldV.Elem()
+	}
+	newV := reflect.ValueOf(new)
+	if newV.Kind() == reflect.Ptr && !newV.IsNil() {
+		newV = newV.Elem()
+	}
+	if oldV.Kind() != reflect.Struct || newV.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("provided values must be structs or non-nil pointers to structs")
+	}
+	if oldV.Type() != newV.Type() {
+		return nil, fmt.Errorf("provided values must have the same type, got %s and %s", oldV.Type(), newV.Type())
+	}
+
+	b := newBinder()
+	oldEntries := b.collectEnvEntries(oldV, "", "", false)
+	newEntries := b.collectEnvEntries(newV, "", "", false)
+
+	newByKey := make(map[string]envEntry, len(newEntries))
+	for _, entry := range newEntries {
+		newByKey[entry.key] = entry
+	}
+
+	var changes []FieldChange
+	seen := make(map[string]bool, len(oldEntries))
+	for _, entry := range oldEntries {
+		seen[entry.key] = true
+		newEntry, ok := newByKey[entry.key]
+		if ok && newEntry.value == entry.value {
+			continue
+		}
+		changes = append(changes, newFieldChange(entry, newEntry, true, ok))
+	}
+	for _, entry := range newEntries {
+		if !seen[entry.key] {
+			changes = append(changes, newFieldChange(envEntry{}, entry, false, true))
+		}
+	}
+	return changes, nil
+}
+
+// newFieldChange describes the change of a field between the old and new entries, where hasOld and hasNew report
+// whether the field exists on each side. The values of secrets are replaced with REDACTED_VALUE.
+func newFieldChange(old, new envEntry, hasOld, hasNew bool) FieldChange {
+	var change FieldChange
+	if hasOld {
+		change.Name, change.Key, change.Old = old.name, old.key, redactEntry(old)
+	}
+	if hasNew {
+		change.Name, change.Key, change.New = new.name, new.key, redactEntry(new)
+	}
+	return change
+}
+
+// redactEntry returns the value of the entry, or REDACTED_VALUE if it is a secret
+func redactEntry(entry envEntry) string {
+	if entry.secret {
+		return REDACTED_VALUE
+	}
+	return entry.value
+}
@@ -0,0 +1,135 @@
+package ectoenv
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDiffEnv(t *testing.T) {
+	type Database struct {
+		URL      string `env:"DATABASE_URL"`
+		Password string `env:"DATABASE_PASSWORD" env-secret:"true"`
+	}
+	type Upstream struct {
+		URL string `env:"URL"`
+	}
+	type Config struct {
+		Host      string            `env:"HOST"`
+		Port      int               `env:"PORT"`
+		Tags      []string          `env:"TAGS"`
+		Labels    map[string]string `env:"LABELS"`
+		Database  Database
+		Upstreams []Upstream `env:"UPSTREAM"`
+	}
+
+	old := Config{
+		Host:      "localhost",
+		Port:      8080,
+		Tags:      []string{"a"},
+		Labels:    map[string]string{"team": "core", "env": "dev"},
+		Database:  Database{URL: "postgres://a", Password: "one"},
+		Upstreams: []Upstream{{URL: "http://a"}, {URL: "http://b"}},
+	}
+
+	tests := []struct {
+		name     string
+		update   func(c *Config)
+		expected []FieldChange
+	}{
+		{
+			name:     "no changes",
+			update:   func(c *Config) {},
+			expected: nil,
+		},
+		{
+			name: "scalar and nested fields",
+			update: func(c *Config) {
+				c.Port = 9090
+				c.Database.URL = "postgres://b"
+			},
+			expected: []FieldChange{
+				{Name: "Port", Key: "PORT", Old: "8080", New: "9090"},
+				{Name: "Database.URL", Key: "DATABASE_URL", Old: "postgres://a", New: "postgres://b"},
+			},
+		},
+		{
+			name: "secrets are redacted",
+			update: func(c *Config) {
+				c.Database.Password = "two"
+			},
+			expected: []FieldChange{
+				{Name: "Database.Password", Key: "DATABASE_PASSWORD", Old: REDACTED_VALUE, New: REDACTED_VALUE},
+			},
+		},
+		{
+			name: "slices and maps",
+			update: func(c *Config) {
+				c.Tags = []string{"a", "b"}
+				c.Labels = map[string]string{"env": "prod", "team": "core"}
+			},
+			expected: []FieldChange{
+				{Name: "Tags", Key: "TAGS", Old: "a", New: "a,b"},
+				{Name: "Labels", Key: "LABELS", Old: "env=dev,team=core", New: "env=prod,team=core"},
+			},
+		},
+		{
+			name: "struct slice elements added and removed",
+			update: func(c *Config) {
+				c.Upstreams = []Upstream{{URL: "http://c"}}
+			},
+			expected: []FieldChange{
+				{Name: "Upstreams[0].URL", Key: "UPSTREAM_0_URL", Old: "http://a", New: "http://c"},
+				{Name: "Upstreams[1].URL", Key: "UPSTREAM_1_URL", Old: "http://b", New: ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := old
+			updated.Tags = append([]string(nil), old.Tags...)
+			updated.Labels = map[string]string{}
+			for k, v := range old.Labels {
+				updated.Labels[k] = v
+			}
+			updated.Upstreams = append([]Upstream(nil), old.Upstreams...)
+			tt.update(&updated)
+
+			changes, err := DiffEnv(&old, &updated)
+			if err != nil {
+				t.Fatalf("DiffEnv() error = %v", err)
+			}
+			if !reflect.DeepEqual(changes, tt.expected) {
+				t.Errorf("DiffEnv() got = %+v, want %+v", changes, tt.expected)
+			}
+		})
+	}
+
+	if _, err := DiffEnv(&old, &Database{}); err == nil {
+		t.Errorf("DiffEnv() expected an error for mismatched types")
+	}
+}
+
+func TestDiffEnvMapsAreOrderIndependent(t *testing.T) {
+	type Config struct {
+		Labels map[string]string `env:"LABELS"`
+	}
+
+	old := Config{Labels: map[string]string{}}
+	updated := Config{Labels: map[string]string{}}
+	for i := 0; i < 50; i++ {
+		old.Labels[fmt.Sprint("key", i)] = "value"
+	}
+	for i := 49; i >= 0; i-- {
+		updated.Labels[fmt.Sprint("key", i)] = "value"
+	}
+
+	changes, err := DiffEnv(&old, &updated)
+	if err != nil {
+		t.Fatalf("DiffEnv() error = %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("DiffEnv() got = %+v for equal maps, want no changes", changes)
+	}
+}
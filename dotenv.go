@@ -0,0 +1,86 @@
+package ectoenv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// readEnvFile reads the dotenv file at path into a map of variables
+func readEnvFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseEnvFile(data)
+}
+
+// parseEnvFile parses dotenv data of the form written by MarshalEnv. Each line is a KEY=VALUE pair, optionally preceded
+// by "export". Blank lines and lines starting with # are ignored. Double quoted values are unquoted like Go strings and
+// may span several lines, keeping the line breaks, which suits PEM keys and certificates. Single quoted values are taken
+// literally, and unquoted values are trimmed of whitespace.
+func parseEnvFile(data []byte) (map[string]string, error) {
+	vars := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNum)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		// a double quoted value without a closing quote on its line continues on the following lines, with each line break
+		// kept as an escaped newline so the value still unquotes like a Go string
+		startLine := lineNum
+		if strings.HasPrefix(value, `"`) && closingQuote(value) < 0 {
+			for closingQuote(value) < 0 {
+				if !scanner.Scan() {
+					return nil, fmt.Errorf("line %d: unterminated quoted value for %s", startLine, key)
+				}
+				lineNum++
+				value += `\n` + strings.TrimRight(scanner.Text(), " \t\r")
+			}
+			if closingQuote(value) != len(value)-1 {
+				return nil, fmt.Errorf("line %d: unexpected text after quoted value for %s", lineNum, key)
+			}
+		}
+
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid quoted value for %s: %w", startLine, key, err)
+			}
+			value = unquoted
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		}
+		vars[key] = value
+	}
+	return vars, scanner.Err()
+}
+
+// closingQuote returns the index of the first unescaped double quote after the opening one of value, or -1 if there is
+// none
+func closingQuote(value string) int {
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
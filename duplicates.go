@@ -0,0 +1,60 @@
+package ectoenv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// duplicateKeys describes every variable that more than one field of rt is bound from, in the order the variables
+// are first used. The fields of slices and maps of structs are checked once, with a placeholder for the index or name.
+func (b *binder) duplicateKeys(rt reflect.Type, prefix string) []string {
+	fields := map[string][]string{}
+	var keys []string
+	b.collectFieldPaths(rt, prefix, "", fields, &keys)
+
+	var duplicates []string
+	for _, key := range keys {
+		if len(fields[key]) > 1 {
+			duplicates = append(duplicates, fmt.Sprintf("%s is used by %s", key, strings.Join(fields[key], ", ")))
+		}
+	}
+	return duplicates
+}
+
+// collectFieldPaths records the path of every field of rt under the variable it is bound from
+func (b *binder) collectFieldPaths(rt reflect.Type, prefix, path string, fields map[string][]string, keys *[]string) {
+	for i := 0; i < rt.NumField(); i++ {
+		structField := b.field(rt, i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		if isNestedStruct(structField.Type) {
+			b.collectFieldPaths(structField.Type, b.nestedPrefix(structField, prefix), path+structField.Name+".", fields, keys)
+			continue
+		}
+		if embedded, ok := embeddedStructType(structField); ok {
+			b.collectFieldPaths(embedded, b.nestedPrefix(structField, prefix), path+structField.Name+".", fields, keys)
+			continue
+		}
+
+		envTag := b.envName(structField)
+		if envTag == "" {
+			continue
+		}
+
+		switch {
+		case isStructSlice(structField.Type):
+			b.collectFieldPaths(structElemType(structField.Type), prefix+envTag+"_<index>_", path+structField.Name+"[].", fields, keys)
+		case isStructMap(structField.Type):
+			b.collectFieldPaths(structElemType(structField.Type), prefix+envTag+"_<name>_", path+structField.Name+"[].", fields, keys)
+		default:
+			key := prefix + envTag
+			if _, ok := fields[key]; !ok {
+				*keys = append(*keys, key)
+			}
+			fields[key] = append(fields[key], path+structField.Name)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package ectoenv
+
+import "testing"
+
+func TestBindEnvWithDetectDuplicates(t *testing.T) {
+	type Server struct {
+		Port int `env:"PORT"`
+	}
+	type Worker struct {
+		Host string `env:"HOST"`
+		Addr string `env:"HOST"`
+	}
+
+	tests := []struct {
+		name    string
+		config  interface{}
+		opts    []Option
+		wantErr string
+	}{
+		{
+			name: "No duplicates",
+			config: &struct {
+				Port      int `env:"PORT"`
+				AdminPort int `env:"ADMIN_PORT"`
+			}{},
+		},
+		{
+			name: "Duplicate fields",
+			config: &struct {
+				Port   int    `env:"PORT"`
+				Server Server `env:"SERVER"`
+				Listen string `env:"LISTEN,PORT"`
+			}{},
+			wantErr: "duplicate environment variables: PORT is used by Port, Server.Port",
+		},
+		{
+			name: "Prefixed nested fields are distinct",
+			config: &struct {
+				Port   int    `env:"PORT"`
+				Server Server `env:"SERVER"`
+			}{},
+			opts: []Option{WithFlattenedNames("_")},
+		},
+		{
+			name: "Duplicate fields of struct slice elements",
+			config: &struct {
+				Workers []Worker `env:"WORKER"`
+			}{},
+			wantErr: "duplicate environment variables: WORKER_<index>_HOST is used by Workers[].Host, Workers[].Addr",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{withLookupMap(map[string]string{}), WithDetectDuplicates(true)}, tt.opts...)
+			err := BindEnvWith(tt.config, opts...)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("BindEnvWith() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("BindEnvWith() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package ectoenv
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ENV_DURATION_FORMAT_TAG is the tag used to select how a time.Duration field is parsed. By default a value starting
+// with P, such as PT1H30M, is parsed as an ISO 8601 duration and any other value with time.ParseDuration; "go" and
+// "iso8601" accept only that format.
+var ENV_DURATION_FORMAT_TAG = "env-duration-format"
+
+// isoDurationUnits maps the designators of an ISO 8601 duration to their length, in the order they must appear. Weeks
+// and days are taken to be exactly 7 and 1 times 24 hours; years and months have no fixed length and are not supported.
+var isoDurationUnits = []struct {
+	designator byte
+	time       bool
+	unit       time.Duration
+}{
+	{'W', false, 7 * 24 * time.Hour},
+	{'D', false, 24 * time.Hour},
+	{'H', true, time.Hour},
+	{'M', true, time.Minute},
+	{'S', true, time.Second},
+}
+
+// parseDuration parses a duration in the format selected by the `env-duration-format` tag
+func parseDuration(format string, envValue string) (time.Duration, error) {
+	switch format {
+	case "":
+		if strings.HasPrefix(strings.TrimLeft(envValue, "+-"), "P") {
+			return parseISODuration(envValue)
+		}
+		return time.ParseDuration(envValue)
+	case "go":
+		return time.ParseDuration(envValue)
+	case "iso8601":
+		return parseISODuration(envValue)
+	}
+	return 0, fmt.Errorf("invalid %s tag %s", ENV_DURATION_FORMAT_TAG, format)
+}
+
+// parseISODuration parses an ISO 8601 duration such as P1DT2H30M or PT0.5S. Every component may have a fraction, with
+// a point or a comma, and a leading sign negates the duration. Years and months are rejected, as they have no fixed
+// length.
+func parseISODuration(envValue string) (time.Duration, error) {
+	value := envValue
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimLeft(value, "+-")
+	if !strings.HasPrefix(value, "P") {
+		return 0, fmt.Errorf("ISO 8601 duration %q must start with P", envValue)
+	}
+	value = value[1:]
+
+	var total time.Duration
+	inTime := false
+	next := 0
+	components := 0
+	for value != "" {
+		if value[0] == 'T' {
+			if inTime {
+				return 0, fmt.Errorf("ISO 8601 duration %q has more than one T", envValue)
+			}
+			inTime = true
+			value = value[1:]
+			continue
+		}
+
+		end := strings.IndexFunc(value, func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != ',' })
+		if end <= 0 {
+			return 0, fmt.Errorf("ISO 8601 duration %q has a component without a number", envValue)
+		}
+		number, designator := value[:end], value[end]
+		value = value[end+1:]
+
+		if !inTime && (designator == 'Y' || designator == 'M') {
+			return 0, fmt.Errorf("ISO 8601 duration %q has a year or month component, which has no fixed length", envValue)
+		}
+
+		i := next
+		for i < len(isoDurationUnits) && (isoDurationUnits[i].designator != designator || isoDurationUnits[i].time != inTime) {
+			i++
+		}
+		if i == len(isoDurationUnits) {
+			return 0, fmt.Errorf("ISO 8601 duration %q has an unexpected component %c", envValue, designator)
+		}
+		next = i + 1
+
+		d, err := durationOf(number, isoDurationUnits[i].unit)
+		if err != nil {
+			return 0, fmt.Errorf("ISO 8601 duration %q: %w", envValue, err)
+		}
+		if total > math.MaxInt64-d {
+			return 0, fmt.Errorf("ISO 8601 duration %q: %w", envValue, strconv.ErrRange)
+		}
+		total += d
+		components++
+	}
+
+	if components == 0 {
+		return 0, fmt.Errorf("ISO 8601 duration %q has no components", envValue)
+	}
+	if negative {
+		total = -total
+	}
+	return total, nil
+}
+
+// durationOf returns number units, where number is a decimal with an optional fraction
+func durationOf(number string, unit time.Duration) (time.Duration, error) {
+	number = strings.Replace(number, ",", ".", 1)
+	whole, fraction, _ := strings.Cut(number, ".")
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("invalid number %s", number)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	n, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if n > math.MaxInt64/int64(unit) {
+		return 0, strconv.ErrRange
+	}
+	d := time.Duration(n) * unit
+
+	if fraction != "" {
+		f, err := strconv.ParseFloat("0."+fraction, 64)
+		if err != nil {
+			return 0, errors.New("invalid fraction " + fraction)
+		}
+		d += time.Duration(math.Round(f * float64(unit)))
+	}
+	return d, nil
+}
+
+// formatISODuration formats a duration as an ISO 8601 duration in hours, minutes and seconds, such as PT1H30M, which
+// parseISODuration parses back to the same value
+func formatISODuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var sb strings.Builder
+	if d < 0 {
+		sb.WriteString("-")
+		d = -d
+	}
+	sb.WriteString("PT")
+	if hours := d / time.Hour; hours > 0 {
+		sb.WriteString(strconv.FormatInt(int64(hours), 10) + "H")
+		d -= hours * time.Hour
+	}
+	if minutes := d / time.Minute; minutes > 0 {
+		sb.WriteString(strconv.FormatInt(int64(minutes), 10) + "M")
+		d -= minutes * time.Minute
+	}
+	if d > 0 {
+		sb.WriteString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S")
+	}
+	return sb.String()
+}
@@ -0,0 +1,134 @@
+package ectoenv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseISODuration(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{value: "PT1H30M", expected: 90 * time.Minute},
+		{value: "PT0.5S", expected: 500 * time.Millisecond},
+		{value: "PT1,5S", expected: 1500 * time.Millisecond},
+		{value: "P1D", expected: 24 * time.Hour},
+		{value: "P2W", expected: 14 * 24 * time.Hour},
+		{value: "P1DT12H", expected: 36 * time.Hour},
+		{value: "PT1.5H", expected: 90 * time.Minute},
+		{value: "PT0S", expected: 0},
+		{value: "-PT10S", expected: -10 * time.Second},
+		{value: "P1Y", wantErr: true},
+		{value: "P1M", wantErr: true},
+		{value: "PT1M2H", wantErr: true},
+		{value: "P1H", wantErr: true},
+		{value: "PT", wantErr: true},
+		{value: "P", wantErr: true},
+		{value: "PTS", wantErr: true},
+		{value: "PT1HT2M", wantErr: true},
+		{value: "PT1X", wantErr: true},
+		{value: "1H", wantErr: true},
+		{value: "P999999999W", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseISODuration(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseISODuration() expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseISODuration() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("parseISODuration() got = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvISODuration(t *testing.T) {
+	type Config struct {
+		Timeout  time.Duration   `env:"TIMEOUT"`
+		Interval time.Duration   `env:"INTERVAL" env-duration-format:"iso8601"`
+		Legacy   time.Duration   `env:"LEGACY" env-duration-format:"go"`
+		Backoffs []time.Duration `env:"BACKOFFS"`
+		Invalid  time.Duration   `env:"INVALID" env-duration-format:"rfc"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected Config
+		wantErr  string
+	}{
+		{
+			name:     "Detected",
+			envVars:  map[string]string{"TIMEOUT": "PT1H30M", "BACKOFFS": "PT1S,2s,P1D"},
+			expected: Config{Timeout: 90 * time.Minute, Backoffs: []time.Duration{time.Second, 2 * time.Second, 24 * time.Hour}},
+		},
+		{
+			name:     "Go format",
+			envVars:  map[string]string{"TIMEOUT": "1h30m", "LEGACY": "5s"},
+			expected: Config{Timeout: 90 * time.Minute, Legacy: 5 * time.Second},
+		},
+		{
+			name:    "Forced ISO 8601",
+			envVars: map[string]string{"INTERVAL": "5s"},
+			wantErr: `unable to set value for field Interval. failed to parse 5s as duration: ISO 8601 duration "5s" must start with P`,
+		},
+		{
+			name:    "Forced Go",
+			envVars: map[string]string{"LEGACY": "PT5S"},
+			wantErr: `unable to set value for field Legacy. failed to parse PT5S as duration: time: invalid duration "PT5S"`,
+		},
+		{
+			name:    "Months",
+			envVars: map[string]string{"TIMEOUT": "P1M"},
+			wantErr: `unable to set value for field Timeout. failed to parse P1M as duration: ISO 8601 duration "P1M" has a year or month component, which has no fixed length`,
+		},
+		{
+			name:    "Invalid format tag",
+			envVars: map[string]string{"INVALID": "PT5S"},
+			wantErr: "unable to set value for field Invalid. invalid env-duration-format tag rfc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, tt.envVars)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvFromMap() error = %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("BindEnvFromMap() got = %+v, want %+v", config, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatISODuration(t *testing.T) {
+	for _, d := range []time.Duration{0, 90 * time.Minute, 36*time.Hour + 1500*time.Millisecond, -10 * time.Second, time.Nanosecond} {
+		formatted := formatISODuration(d)
+		parsed, err := parseISODuration(formatted)
+		if err != nil || parsed != d {
+			t.Errorf("formatISODuration(%v) = %s, which parses to %v, %v", d, formatted, parsed, err)
+		}
+	}
+	if got := formatISODuration(90 * time.Minute); got != "PT1H30M" {
+		t.Errorf("formatISODuration() got = %s, want PT1H30M", got)
+	}
+}
@@ -0,0 +1,46 @@
+package ectoenv
+
+import "reflect"
+
+// embeddedStructType returns the struct type of an embedded pointer to a struct, such as *Telemetry, whose fields are
+// bound as if the struct were embedded by value
+func embeddedStructType(field reflect.StructField) (reflect.Type, bool) {
+	if field.Anonymous && field.Type.Kind() == reflect.Ptr && isNestedStruct(field.Type.Elem()) {
+		return field.Type.Elem(), true
+	}
+	return nil, false
+}
+
+// setEmbeddedStructField binds the struct that an embedded pointer points to, sharing the prefix of the parent like a
+// struct embedded by value. A nil pointer is allocated only when at least one of the struct's variables is set, so that
+// it stays nil when there is nothing to bind. With WithClearOnUnset, a pointer is reset to nil once none are set.
+func (b *binder) setEmbeddedStructField(field reflect.Value, structField reflect.StructField, prefix string) error {
+	rt, _ := embeddedStructType(structField)
+	nested := b.nestedPrefix(structField, prefix)
+	if (field.IsNil() || b.clearOnUnset) && !b.anyKeySet(rt, nested) {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	if !field.IsNil() {
+		return b.setFieldValues(field.Elem(), nested)
+	}
+	ptr := reflect.New(rt)
+	restoreTracking := b.untracked()
+	defer restoreTracking()
+	if err := b.setFieldValues(ptr.Elem(), nested); err != nil {
+		return err
+	}
+	field.Set(ptr)
+	return nil
+}
+
+// anyKeySet reports whether any variable that the fields of rt may be bound from is set
+func (b *binder) anyKeySet(rt reflect.Type, prefix string) bool {
+	for _, key := range b.fieldKeys(rt, prefix) {
+		if _, ok := b.lookup(key); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package ectoenv
+
+import (
+	"strings"
+	"testing"
+)
+
+type Telemetry struct {
+	Endpoint   string `env:"OTEL_ENDPOINT" env-required:"true"`
+	SampleRate int    `env:"OTEL_SAMPLE_RATE" env-default:"10"`
+}
+
+type telemetry struct {
+	Endpoint string `env:"PRIVATE_OTEL_ENDPOINT"`
+}
+
+func TestBindEnvEmbeddedPointer(t *testing.T) {
+	type Config struct {
+		*Telemetry
+		*telemetry
+		Port int `env:"PORT"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected *Telemetry
+		wantErr  string
+	}{
+		{
+			name:     "Set",
+			envVars:  map[string]string{"PORT": "8080", "OTEL_ENDPOINT": "http://collector", "PRIVATE_OTEL_ENDPOINT": "ignored"},
+			expected: &Telemetry{Endpoint: "http://collector", SampleRate: 10},
+		},
+		{
+			name:     "Unset",
+			envVars:  map[string]string{"PORT": "8080"},
+			expected: nil,
+		},
+		{
+			name:    "Missing required",
+			envVars: map[string]string{"PORT": "8080", "OTEL_SAMPLE_RATE": "50"},
+			wantErr: "missing required environment variables: OTEL_ENDPOINT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, tt.envVars)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvFromMap() error = %v", err)
+			}
+			if config.Port != 8080 {
+				t.Errorf("BindEnvFromMap() got Port = %d, want 8080", config.Port)
+			}
+			if (config.Telemetry == nil) != (tt.expected == nil) || (tt.expected != nil && *config.Telemetry != *tt.expected) {
+				t.Errorf("BindEnvFromMap() got Telemetry = %v, want %v", config.Telemetry, tt.expected)
+			}
+			if config.telemetry != nil {
+				t.Errorf("BindEnvFromMap() allocated an unexported embedded pointer")
+			}
+		})
+	}
+}
+
+func TestBindEnvEmbeddedPointerPrefix(t *testing.T) {
+	type Config struct {
+		*Telemetry `env-prefix:"APP_"`
+	}
+
+	config := Config{Telemetry: &Telemetry{Endpoint: "http://old", SampleRate: 1}}
+	err := BindEnvWith(&config, withLookupMap(map[string]string{"APP_OTEL_ENDPOINT": "http://new"}))
+	if err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+	if config.Endpoint != "http://new" || config.SampleRate != 10 {
+		t.Errorf("BindEnvWith() got = %v", *config.Telemetry)
+	}
+
+	data, err := MarshalEnv(config)
+	if err != nil {
+		t.Fatalf("MarshalEnv() error = %v", err)
+	}
+	if !strings.Contains(string(data), "APP_OTEL_ENDPOINT=http://new\n") {
+		t.Errorf("MarshalEnv() got = %s", data)
+	}
+
+	docs := Describe(&config)
+	if len(docs) != 2 || docs[0].Key != "APP_OTEL_ENDPOINT" || docs[0].Field != "Telemetry.Endpoint" {
+		t.Errorf("Describe() got = %+v", docs)
+	}
+}
+
+func TestBindEnvEmbeddedPointerClearOnUnset(t *testing.T) {
+	type Config struct {
+		*Telemetry
+	}
+
+	config := Config{Telemetry: &Telemetry{Endpoint: "http://old"}}
+	if err := BindEnvWith(&config, withLookupMap(map[string]string{}), WithClearOnUnset(true)); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+	if config.Telemetry != nil {
+		t.Errorf("BindEnvWith() got Telemetry = %v, want nil", config.Telemetry)
+	}
+}
@@ -0,0 +1,25 @@
+package ectoenv
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+)
+
+// ENV_ENCODING_TAG is the tag used to decode a []byte field from a text encoding of its contents, e.g. "hex" to decode
+// a hex-encoded key. A []byte field without the tag is bound as a slice of numbers.
+var ENV_ENCODING_TAG = "env-encoding"
+
+// setEncodedBytesField decodes a []byte field from the encoding named by its `env-encoding` tag
+func setEncodedBytesField(field reflect.Value, name string, encoding string, envValue string) error {
+	switch encoding {
+	case "hex":
+		val, err := hex.DecodeString(envValue)
+		if err != nil {
+			return &ParseError{Name: name, Value: envValue, Kind: "hex", Err: err}
+		}
+		field.SetBytes(val)
+		return nil
+	}
+	return fmt.Errorf("unable to set value for field %s. unknown %s tag %s", name, ENV_ENCODING_TAG, encoding)
+}
@@ -0,0 +1,66 @@
+package ectoenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBindEnvHexBytes(t *testing.T) {
+	type Config struct {
+		Key  []byte `env:"KEY" env-encoding:"hex" env-min-len:"4"`
+		Raw  []byte `env:"RAW"`
+		Salt []byte `env:"SALT" env-encoding:"base32"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected Config
+		wantErr  string
+	}{
+		{
+			name:     "Hex",
+			envVars:  map[string]string{"KEY": "DEADbeef01", "RAW": "1,2"},
+			expected: Config{Key: []byte{0xde, 0xad, 0xbe, 0xef, 0x01}, Raw: []byte{1, 2}},
+		},
+		{
+			name:    "Odd length",
+			envVars: map[string]string{"KEY": "abc"},
+			wantErr: "unable to set value for field Key. failed to parse abc as hex: encoding/hex: odd length hex string",
+		},
+		{
+			name:    "Not hex",
+			envVars: map[string]string{"KEY": "zz"},
+			wantErr: "unable to set value for field Key. failed to parse zz as hex: encoding/hex: invalid byte: U+007A 'z'",
+		},
+		{
+			name:    "Too short",
+			envVars: map[string]string{"KEY": "abcd"},
+			wantErr: "unable to set value for field Key. expected at least 4 elements, got 2",
+		},
+		{
+			name:    "Unknown encoding",
+			envVars: map[string]string{"KEY": "DEADbeef", "SALT": "abcd"},
+			wantErr: "unable to set value for field Salt. unknown env-encoding tag base32",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, tt.envVars)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvFromMap() error = %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("BindEnvFromMap() got = %v, want %v", config, tt.expected)
+			}
+		})
+	}
+}
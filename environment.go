@@ -1,22 +1,144 @@
 package ectoenv
 
 import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 )
 
+// ENV_TAG is the tag used to name the variables of a field. It is read when a bind starts; use WithEnvTag to bind with
+// another tag without affecting concurrent binds.
 var ENV_TAG = "env"
 
+// ENV_DEFAULT_TAG is the tag used to specify the default value of a field. It is read when a bind starts; use
+// WithDefaultTag to bind with another tag without affecting concurrent binds.
 var ENV_DEFAULT_TAG = "env-default"
 
+// ENV_LAYOUT_TAG is the tag used to specify the layout of a time.Time field. The layout may be any layout accepted by
+// time.Parse, or one of the sentinels "unix" and "unixmilli" to parse the value as a Unix epoch timestamp.
+var ENV_LAYOUT_TAG = "env-layout"
+
+// DEFAULT_TIME_LAYOUT is the layout used to parse time.Time fields that do not specify a layout
+var DEFAULT_TIME_LAYOUT = time.RFC3339
+
+var timeType = reflect.TypeOf(time.Time{})
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// ENV_DEFAULT_FROM_TAG is the tag used to name another environment variable whose value is used when the field's own
+// variable is unset. It takes precedence over ENV_DEFAULT_TAG.
+var ENV_DEFAULT_FROM_TAG = "env-default-from"
+
+// ENV_BASE_TAG is the tag used to specify the base of an integer field. A base of 0 detects the base from the prefix of
+// the value, e.g. 0x for hexadecimal, 0o for octal and 0b for binary. The default is base 10.
+var ENV_BASE_TAG = "env-base"
+
+// ENV_ONEOF_TAG is the tag used to restrict a field to a comma separated list of allowed values
+var ENV_ONEOF_TAG = "env-oneof"
+
+// ENV_ONEOF_FOLD_TAG is the tag used to compare a value against the allowed values of ENV_ONEOF_TAG case-insensitively.
+// When set to "true" the value is stored in its lowercase form.
+var ENV_ONEOF_FOLD_TAG = "env-oneof-fold"
+
+// ENV_SEPARATOR_TAG is the tag used to specify the separator of a slice field. Escape sequences such as \n and \t are
+// decoded.
+var ENV_SEPARATOR_TAG = "env-separator"
+
+// DEFAULT_SEPARATOR is the separator used to split slice fields that do not specify a separator
+var DEFAULT_SEPARATOR = ","
+
+// ENV_FORMAT_TAG is the tag used to specify the encoding of a value, e.g. "json" to decode a slice from a JSON array or
+// "pem" to decode a PEM block into a []byte field
+var ENV_FORMAT_TAG = "env-format"
+
+// ENV_VALUE_SEPARATOR_TAG is the tag used to specify the separator of the slice values of a map field
+var ENV_VALUE_SEPARATOR_TAG = "env-value-separator"
+
+// DEFAULT_VALUE_SEPARATOR is the separator used to split the slice values of map fields that do not specify one
+var DEFAULT_VALUE_SEPARATOR = "|"
+
+// ENV_SKIP_EMPTY_TAG is the tag used to drop empty elements when splitting a slice, so that "a,,b" and "a,b," bind to
+// the same two elements
+var ENV_SKIP_EMPTY_TAG = "env-skip-empty"
+
+// ENV_PREFIX_TAG is the tag used to set the prefix of the variables of a nested struct, which is appended to the
+// prefix of the parent
+var ENV_PREFIX_TAG = "env-prefix"
+
+// ABSOLUTE_PREFIX_MARKER marks an `env-prefix` tag that replaces every inherited prefix, including one set with
+// WithPrefix, rather than being appended to it. For example, `env-prefix:"/LOG_"` always reads LOG_LEVEL.
+var ABSOLUTE_PREFIX_MARKER = "/"
+
+// ENV_REQUIRED_IF_TAG is the tag used to make a field required only when the named bool field of the same struct is true
+var ENV_REQUIRED_IF_TAG = "env-required-if"
+
+// ENV_UNQUOTE_TAG is the tag used to strip a matching pair of single or double quotes surrounding the value and each
+// element of a slice or map, as left by some shells
+var ENV_UNQUOTE_TAG = "env-unquote"
+
+// ENV_BOOL_MODE_TAG is the tag used to select how bool fields are parsed. The default, "standard", is strconv.ParseBool;
+// "extended" also accepts yes/no, on/off, y/n and enable(d)/disable(d) in any case; "numeric" parses a base 10 integer
+// and is true when it is nonzero, for legacy flags such as FEATURE=2.
+var ENV_BOOL_MODE_TAG = "env-bool-mode"
+
+// ENV_DIGIT_SEPARATORS_TAG is the tag used to list characters that are removed from the value of an integer or float
+// field before it is parsed, e.g. "_" to accept Go-style 1_000_000 or "," to accept 1,000,000
+var ENV_DIGIT_SEPARATORS_TAG = "env-digit-separators"
+
+// ENV_GROUP_TAG is the tag used to assign a field to one or more comma separated groups, which BindEnvGroup binds on
+// their own
+var ENV_GROUP_TAG = "env-group"
+
+// ENV_MIN_TAG is the tag used to specify the smallest value allowed for a numeric or duration field
+var ENV_MIN_TAG = "env-min"
+
+// ENV_MAX_TAG is the tag used to specify the largest value allowed for a numeric or duration field
+var ENV_MAX_TAG = "env-max"
+
+// ENV_MIN_LEN_TAG is the tag used to specify the minimum number of elements of a slice or map field, or of characters of
+// a string field
+var ENV_MIN_LEN_TAG = "env-min-len"
+
+// ENV_MAX_LEN_TAG is the tag used to specify the maximum number of elements of a slice or map field, or of characters of
+// a string field
+var ENV_MAX_LEN_TAG = "env-max-len"
+
+// ENV_QUOTED_TAG is the tag used to split a slice field as a CSV record, so that quoted elements may contain commas
+var ENV_QUOTED_TAG = "env-quoted"
+
+// ENV_TRANSFORM_TAG is the tag used to apply a comma separated list of registered transformers to a value before it is
+// converted to the field's type
+var ENV_TRANSFORM_TAG = "env-transform"
+
+// ENV_ALLOW_EMPTY_TAG is the tag used to let a variable that is set to an empty value override the default, clearing
+// the field to its zero value
+var ENV_ALLOW_EMPTY_TAG = "env-allow-empty"
+
+// ENV_UNIT_TAG is the tag used to parse a value with a unit suffix, e.g. "bytes" to parse "10MB" into an int field
+var ENV_UNIT_TAG = "env-unit"
+
+// ENV_SECRET_TAG is the tag used to mark a field as secret so that its value is redacted when the struct is serialized
+var ENV_SECRET_TAG = "env-secret"
+
+// ENV_REQUIRED_TAG is the tag used to mark a field as required. Binding fails without modifying the struct if a
+// required field has neither a value nor a default.
+var ENV_REQUIRED_TAG = "env-required"
+
 // AUTO_REFRESH_INTERVAL is the interval in seconds to refresh the environment variables
 var AUTO_REFRESH_INTERVAL = 60
 
+// DEFAULT_REFRESH_ERROR_INTERVAL is the number of refresh cycles between reports of the same refresh failure
+var DEFAULT_REFRESH_ERROR_INTERVAL = 10
+
 // BindEnv sets the values of the provided struct based on the values of the environment variables
 // defined in the struct's tags. The struct must be a non-nil pointer to a struct.
 // v: a non-nil pointer to a struct
@@ -27,188 +149,1699 @@ func BindEnv(v interface{}) error {
 		return err
 	}
 
-	return setFieldValues(rv)
-}
+	return newBinder().bind(rv)
+}
+
+// BindEnvFromMap is like BindEnv but looks up values in the provided map instead of the environment of the process.
+// Defaults and all other tags are honored as they are by BindEnv.
+// v: a non-nil pointer to a struct
+// m: the map of variable names to values to bind from
+// returns: an error if the provided value is not a non-nil pointer to a struct or if the value of a variable cannot be
+// converted to the type of its field
+func BindEnvFromMap(v interface{}, m map[string]string) error {
+	return BindEnvWith(v, withLookupMap(m))
+}
+
+// BindEnvFromEnviron is like BindEnvFromMap but binds from variables in the KEY=VALUE form returned by os.Environ, such
+// as the captured environment of another process. When a key appears more than once, the last value wins. Entries
+// without a = are ignored.
+// v: a non-nil pointer to a struct
+// environ: the variables to bind from, each in the form KEY=VALUE
+// returns: an error if the provided value is not a non-nil pointer to a struct or if the value of a variable cannot be
+// converted to the type of its field
+func BindEnvFromEnviron(v interface{}, environ []string) error {
+	m := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if key, value, ok := strings.Cut(kv, "="); ok && key != "" {
+			m[key] = value
+		}
+	}
+	return BindEnvFromMap(v, m)
+}
+
+// BindEnvStrict is like BindEnv but returns an error as soon as it encounters a tagged field whose type it cannot bind,
+// rather than silently leaving the field unset. It is intended to catch unsupported fields during development.
+// v: a non-nil pointer to a struct
+// returns: an error if the provided value is not a non-nil pointer to a struct, if a tagged field has an unsupported
+// type or if the value of an environment variable cannot be converted to the type of its field
+func BindEnvStrict(v interface{}) error {
+	return BindEnvWith(v, WithStrict(true))
+}
+
+// MustBindEnv is like BindEnv but panics if the environment variables cannot be bound. It is intended for use during
+// program initialization, where a configuration error is fatal, and should not be used once the program is running.
+// v: a non-nil pointer to a struct
+func MustBindEnv(v interface{}) {
+	if err := BindEnv(v); err != nil {
+		panic(err)
+	}
+}
+
+// ResetDefaults resets the fields of the provided struct to the values of their `env-default` tags, or to their zero
+// values when no default is set, without consulting the environment. Nested structs are reset recursively and fields
+// without an `env` tag are left unchanged.
+// v: a non-nil pointer to a struct
+// returns: an error if the provided value is not a non-nil pointer to a struct or if a default value cannot be parsed
+func ResetDefaults(v interface{}) error {
+	rv, err := validateInput(v)
+	if err != nil {
+		return err
+	}
+
+	return newBinder().resetFieldValues(rv)
+}
+
+func (b *binder) resetFieldValues(rv reflect.Value) error {
+	rt := rv.Type()
+	var templates []templateDefault
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Field(i)
+		structField := b.field(rt, i)
+		if !field.CanSet() {
+			continue
+		}
+
+		if isNestedStruct(field.Type()) {
+			if err := b.resetFieldValues(field); err != nil {
+				return fmt.Errorf("unable to reset value for field %s: %w", structField.Name, err)
+			}
+			continue
+		}
+
+		if _, ok := embeddedStructType(structField); ok {
+			if !field.IsNil() {
+				if err := b.resetFieldValues(field.Elem()); err != nil {
+					return fmt.Errorf("unable to reset value for field %s: %w", structField.Name, err)
+				}
+			}
+			continue
+		}
+
+		if len(envTagNames(structField)) == 0 {
+			continue
+		}
+
+		if isTemplateDefault(structField) && getTag(structField, canonicalDefaultTag) != "" {
+			templates = append(templates, templateDefault{field: field, structField: structField})
+			continue
+		}
+
+		if err := b.assignValue(rv, field, structField, getTag(structField, canonicalDefaultTag)); err != nil {
+			return err
+		}
+	}
+
+	return b.setTemplateDefaults(rv, templates)
+}
+
+func validateInput(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return reflect.Value{}, errors.New("provided value must be a non-nil pointer to a struct")
+	}
+
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, errors.New("provided value must be a pointer to a struct")
+	}
+
+	return rv, nil
+}
+
+// bind verifies that every required field can be satisfied before setting any field of rv, so that a missing variable
+// does not leave the struct partially bound
+func (b *binder) bind(rv reflect.Value) error {
+	if b.requirePrefixPresent && b.prefix != "" && b.unlisted {
+		return errors.New("WithRequirePrefixPresent cannot be used with WithLookup, which cannot list its variables")
+	}
+
+	if b.defaultsFile != "" {
+		defaults, err := readEnvFile(b.defaultsFile)
+		if err != nil {
+			return fmt.Errorf("unable to read defaults file %s: %w", b.defaultsFile, err)
+		}
+		b.fileDefaults = defaults
+	}
+
+	if b.detectDuplicates {
+		if duplicates := b.duplicateKeys(rv.Type(), b.prefix); len(duplicates) > 0 {
+			return fmt.Errorf("duplicate environment variables: %s", strings.Join(duplicates, "; "))
+		}
+	}
+
+	if b.emptyAsUnset {
+		lookup, environ := b.lookup, b.environ
+		b.lookup = func(key string) (string, bool) {
+			value, ok := lookup(key)
+			return value, ok && (value != "" || b.allowEmpty)
+		}
+		b.environ = func() []string {
+			var nonEmpty []string
+			for _, kv := range environ() {
+				if !strings.HasSuffix(kv, "=") {
+					nonEmpty = append(nonEmpty, kv)
+				}
+			}
+			return nonEmpty
+		}
+		defer func() { b.lookup, b.environ = lookup, environ }()
+	}
+
+	if b.keyTransformer != nil {
+		lookup := b.lookup
+		b.lookup = func(key string) (string, bool) {
+			return lookup(b.keyTransformer(key))
+		}
+		defer func() { b.lookup = lookup }()
+	}
+
+	if len(b.aliases) > 0 {
+		lookup := b.lookup
+		b.lookup = func(key string) (string, bool) {
+			value, ok := lookup(key)
+			if value != "" {
+				return value, ok
+			}
+			if alias, found := b.aliases[key]; found {
+				if aliasValue, aliasOk := lookup(alias); aliasOk {
+					return aliasValue, aliasOk
+				}
+			}
+			return value, ok
+		}
+		defer func() { b.lookup = lookup }()
+	}
+
+	if b.requirePrefixPresent && b.prefix != "" && !b.hasEnvPrefix(b.prefix) {
+		return fmt.Errorf("no environment variables with prefix %s are set", b.transformKey(b.prefix))
+	}
+
+	missing := &MissingRequiredError{}
+	b.missingRequired(rv.Type(), rv, b.prefix, "", missing)
+	if len(missing.Keys) > 0 {
+		return missing
+	}
+
+	if b.rejectUnknownPrefix == "" {
+		return b.setFieldValues(rv, b.prefix)
+	}
+
+	// record every variable that is looked up, so that variables with the prefix that no field consumed can be reported
+	consumed := map[string]bool{}
+	b.skippedKeys = nil
+	lookup := b.lookup
+	b.lookup = func(key string) (string, bool) {
+		consumed[b.transformKey(key)] = true
+		if alias, ok := b.aliases[key]; ok {
+			consumed[b.transformKey(alias)] = true
+		}
+		return lookup(key)
+	}
+	defer func() { b.lookup = lookup }()
+
+	err := b.setFieldValues(rv, b.prefix)
+	if err != nil && b.errorMode != Collect {
+		return err
+	}
+
+	var unknown []string
+	for _, kv := range b.environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(key, b.rejectUnknownPrefix) && !consumed[key] && !b.skipped(key) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		unknownErr := fmt.Errorf("unknown environment variables with prefix %s: %s", b.rejectUnknownPrefix, strings.Join(unknown, ", "))
+		return errors.Join(err, unknownErr)
+	}
+	return err
+}
+
+// skipKeys records the variables of a field skipped by WithOnlyIfZero, so that WithRejectUnknown does not report them
+func (b *binder) skipKeys(structField reflect.StructField, prefix string) {
+	if b.rejectUnknownPrefix == "" {
+		return
+	}
+
+	composite := isStructSlice(structField.Type) || isStructMap(structField.Type) || getTag(structField, ENV_INDEXED_TAG) == "true"
+	for _, name := range b.envNames(structField) {
+		key := b.transformKey(prefix + name)
+		b.skippedKeys = append(b.skippedKeys, key)
+		if composite {
+			b.skippedKeys = append(b.skippedKeys, key+"_")
+		}
+	}
+}
+
+// skipped reports whether key belongs to a field skipped by WithOnlyIfZero
+func (b *binder) skipped(key string) bool {
+	for _, skipped := range b.skippedKeys {
+		if key == skipped || (strings.HasSuffix(skipped, "_") && strings.HasPrefix(key, skipped)) {
+			return true
+		}
+	}
+	return false
+}
+
+// missingRequired adds to missing the variables of required fields in rt that have neither a value nor a default,
+// along with the paths of those fields below path
+func (b *binder) missingRequired(rt reflect.Type, rv reflect.Value, prefix, path string, missing *MissingRequiredError) {
+	for i := 0; i < rt.NumField(); i++ {
+		structField := b.field(rt, i)
+		if !structField.IsExported() || b.skipGroup(structField) {
+			continue
+		}
+
+		// field is the current value of the field, or the zero Value when the struct is not allocated yet
+		var field reflect.Value
+		if rv.IsValid() {
+			field = rv.Field(i)
+		}
+
+		if isNestedStruct(structField.Type) {
+			restore := b.enterGroup(structField)
+			b.missingRequired(structField.Type, field, b.nestedPrefix(structField, prefix), path+structField.Name+".", missing)
+			restore()
+			continue
+		}
+
+		// the fields of an embedded pointer are only required once one of them is set, since it is otherwise left nil
+		if embedded, ok := embeddedStructType(structField); ok {
+			nested := b.nestedPrefix(structField, prefix)
+			if b.anyKeySet(embedded, nested) {
+				var elem reflect.Value
+				if field.IsValid() && !field.IsNil() {
+					elem = field.Elem()
+				}
+				restore := b.enterGroup(structField)
+				b.missingRequired(embedded, elem, nested, path+structField.Name+".", missing)
+				restore()
+			}
+			continue
+		}
+
+		envTag := b.envName(structField)
+		if envTag == "" {
+			continue
+		}
+
+		// with WithOnlyIfZero, a field that already holds a value is not bound, so it satisfies env-required
+		if b.onlyIfZero && field.IsValid() && !field.IsZero() {
+			continue
+		}
+		required := getTag(structField, ENV_REQUIRED_TAG) == "true"
+
+		if isStructSlice(structField.Type) {
+			j := 0
+			for ; b.hasEnvPrefix(fmt.Sprintf("%s%s_%d_", prefix, envTag, j)); j++ {
+				elemPath := fmt.Sprintf("%s%s[%d].", path, structField.Name, j)
+				b.missingRequired(structElemType(structField.Type), reflect.Value{}, fmt.Sprintf("%s%s_%d_", prefix, envTag, j), elemPath, missing)
+			}
+			if required && j == 0 {
+				missing.add(fmt.Sprintf("%s%s_0_*", prefix, envTag), path+structField.Name)
+			}
+			continue
+		}
+
+		if isStructMap(structField.Type) {
+			names := b.structMapNames(structElemType(structField.Type), prefix+envTag+"_")
+			for _, name := range names {
+				elemPath := path + structField.Name + "[" + name + "]."
+				b.missingRequired(structElemType(structField.Type), reflect.Value{}, prefix+envTag+"_"+name+"_", elemPath, missing)
+			}
+			if required && len(names) == 0 {
+				missing.add(fmt.Sprintf("%s%s_<name>_*", prefix, envTag), path+structField.Name)
+			}
+			continue
+		}
+
+		if b.requireAll && getTag(structField, canonicalDefaultTag) == "" {
+			required = true
+		}
+		// a slice, map or string that needs at least one element or character cannot be satisfied by leaving its variable
+		// unset
+		if minLen, err := strconv.Atoi(getTag(structField, ENV_MIN_LEN_TAG)); err == nil && minLen > 0 && hasLength(structField.Type) {
+			required = true
+		}
+		if !required {
+			continue
+		}
+		if isIndexedSlice(structField) && len(b.indexedValues(prefix+envTag)) > 0 {
+			continue
+		}
+		if _, ok := b.getEnvValue(structField, prefix); !ok {
+			missing.add(prefix+envTag, path+structField.Name)
+		}
+	}
+}
+
+// setFieldValues sets the fields of rv, prepending prefix to the name of each environment variable
+func (b *binder) setFieldValues(rv reflect.Value, prefix string) error {
+	rt := rv.Type()
+	var templates []templateDefault
+	var errs []error
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Field(i)
+		structField := b.field(rt, i)
+		if !field.CanSet() || b.skipGroup(structField) {
+			continue
+		}
+
+		if selfBinder, ok := asSelfBinder(field); ok {
+			if err := b.bindSelf(selfBinder, b.nestedPrefix(structField, prefix)); err != nil {
+				if err := b.fail(&errs, fmt.Errorf("unable to set value for field %s: %w", structField.Name, err)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if _, ok := embeddedStructType(structField); ok {
+			restoreGroup := b.enterGroup(structField)
+			restorePath := b.enterPath(structField.Name + ".")
+			err := b.setEmbeddedStructField(field, structField, prefix)
+			restorePath()
+			restoreGroup()
+			if err != nil {
+				if err := b.fail(&errs, fmt.Errorf("unable to set value for field %s: %w", structField.Name, err)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if isNestedStruct(field.Type()) && getTag(structField, ENV_SETTER_TAG) == "" {
+			restoreGroup := b.enterGroup(structField)
+			restorePath := b.enterPath(structField.Name + ".")
+			err := b.setFieldValues(field, b.nestedPrefix(structField, prefix))
+			restorePath()
+			restoreGroup()
+			if err != nil {
+				if err := b.fail(&errs, fmt.Errorf("unable to set value for field %s: %w", structField.Name, err)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		envTag := b.envName(structField)
+		if envTag == "" {
+			continue
+		}
+
+		// with WithOnlyIfZero, a field that already holds a value keeps it
+		if b.onlyIfZero && !field.IsZero() {
+			b.skipKeys(structField, prefix)
+			continue
+		}
+
+		if isStructSlice(field.Type()) {
+			restorePath := b.enterPath(structField.Name)
+			err := b.setStructSliceField(field, prefix+envTag)
+			restorePath()
+			if err != nil {
+				if err := b.fail(&errs, fmt.Errorf("unable to set value for field %s: %w", structField.Name, err)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if isStructMap(field.Type()) {
+			restorePath := b.enterPath(structField.Name)
+			err := b.setStructMapField(field, prefix+envTag)
+			restorePath()
+			if err != nil {
+				if err := b.fail(&errs, fmt.Errorf("unable to set value for field %s: %w", structField.Name, err)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if b.strict && !isSupportedType(field.Type()) && getTag(structField, ENV_SETTER_TAG) == "" {
+			err := fmt.Errorf("unable to set value for field %s. unsupported type %s of kind %s", structField.Name, field.Type(), field.Kind())
+			if err := b.fail(&errs, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// an indexed slice without any indexed variables falls back to its default
+		if isIndexedSlice(structField) {
+			if values := b.indexedValues(prefix + envTag); len(values) > 0 {
+				err := b.setIndexedSliceField(field, structField, values)
+				if err == nil {
+					err = validateRequiredElements(field, structField)
+				}
+				b.observe(structField, indexedKey(prefix+envTag, 0), SourceEnv, err)
+				if err != nil && b.lenient {
+					b.handleError(err)
+				} else if err != nil {
+					if err := b.fail(&errs, err); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+		}
+
+		envValue, key, source := b.resolveEnvValue(structField, prefix)
+		seen := fmt.Sprintf("%s %s=%s", source, key, envValue)
+		if b.fieldUnchanged(structField, seen) {
+			b.observe(structField, key, source, nil)
+			continue
+		}
+
+		if source == SourceUnset {
+			// an empty value stores the zero value, atomically for atomic and Value fields
+			if b.clearOnUnset {
+				if err := b.assignValue(rv, field, structField, ""); err != nil {
+					b.forgetField(structField)
+					if err := b.fail(&errs, err); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+			b.recordField(structField, seen)
+			b.observe(structField, key, source, nil)
+			continue
+		}
+
+		if source == SourceDefault && isTemplateDefault(structField) {
+			// the rendered default depends on other fields, so it is never skipped
+			b.forgetField(structField)
+			templates = append(templates, templateDefault{field: field, structField: structField, key: key})
+			continue
+		}
+
+		err := b.assignValue(rv, field, structField, envValue)
+		if err == nil {
+			err = validateRequiredElements(field, structField)
+		}
+		b.observe(structField, key, source, err)
+		if err == nil {
+			b.recordField(structField, seen)
+		} else {
+			b.forgetField(structField)
+			if !b.lenient {
+				if err := b.fail(&errs, err); err != nil {
+					return err
+				}
+				continue
+			}
+			b.handleError(err)
+
+			// fall back to the default, leaving the field unchanged if the default is also invalid
+			defaultValue := getTag(structField, canonicalDefaultTag)
+			if defaultValue != "" && defaultValue != envValue {
+				if err := b.assignValue(rv, field, structField, defaultValue); err != nil {
+					b.handleError(err)
+				}
+			}
+		}
+	}
+
+	if err := b.setTemplateDefaults(rv, templates); err != nil {
+		if err := b.fail(&errs, err); err != nil {
+			return err
+		}
+	}
+
+	if err := b.checkRequiredIf(rv, prefix); err != nil {
+		if err := b.fail(&errs, err); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkRequiredIf checks the fields of rv tagged with `env-required-if` once their siblings are bound, requiring a value
+// for each field whose condition field is true
+func (b *binder) checkRequiredIf(rv reflect.Value, prefix string) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		structField := b.field(rt, i)
+		condition := getTag(structField, ENV_REQUIRED_IF_TAG)
+		if condition == "" || !structField.IsExported() || b.skipGroup(structField) {
+			continue
+		}
+
+		conditionType, ok := rt.FieldByName(condition)
+		if !ok || (conditionType.Type.Kind() != reflect.Bool && conditionType.Type != reflect.TypeOf((*bool)(nil))) {
+			return fmt.Errorf("unable to set value for field %s. invalid %s tag %s: not a bool field", structField.Name, ENV_REQUIRED_IF_TAG, condition)
+		}
+
+		// a nil *bool is false
+		conditionField := reflect.Indirect(rv.FieldByName(condition))
+		if !conditionField.IsValid() || !conditionField.Bool() {
+			continue
+		}
+
+		if _, ok := b.getEnvValue(structField, prefix); !ok {
+			return fmt.Errorf("unable to set value for field %s. %s is required when %s is true", structField.Name, prefix+b.envName(structField), condition)
+		}
+	}
+	return nil
+}
+
+// parseOptions holds the options that change how the value of a field is parsed, resolved from the tags of the field and
+// the options of the binder
+type parseOptions struct {
+	// unquote strips a matching pair of quotes surrounding the value, and each element and map key and value
+	unquote bool
+	// boolMode selects how bool values are parsed, as the `env-bool-mode` tag does
+	boolMode string
+	// digitSeparators lists the characters removed from integer and float values before they are parsed
+	digitSeparators string
+}
+
+// parseOptions resolves the parse options of the field. Tags set on the field take precedence over the binder's options.
+func (b *binder) parseOptions(field reflect.StructField) parseOptions {
+	opts := parseOptions{
+		unquote:         getTag(field, ENV_UNQUOTE_TAG) == "true",
+		boolMode:        getTag(field, ENV_BOOL_MODE_TAG),
+		digitSeparators: getTag(field, ENV_DIGIT_SEPARATORS_TAG),
+	}
+	if !hasTag(field, ENV_UNQUOTE_TAG) {
+		opts.unquote = b.unquote
+	}
+	if b.extendedBools && !hasTag(field, ENV_BOOL_MODE_TAG) {
+		opts.boolMode = "extended"
+	}
+	if b.underscoreDigits && !hasTag(field, ENV_DIGIT_SEPARATORS_TAG) {
+		opts.digitSeparators = "_"
+	}
+	return opts
+}
+
+// prepareValue returns the value unquoted, transformed and with its affixes removed
+func (b *binder) prepareValue(structField reflect.StructField, opts parseOptions, envValue string) (string, error) {
+	if opts.unquote {
+		envValue = unquoteValue(envValue)
+	}
+
+	envValue, err := applyTransformers(structField, envValue)
+	if err != nil {
+		return "", err
+	}
+
+	// slices remove the affixes from each element as it is parsed
+	if trimsWholeValue(structField.Type) {
+		envValue = trimAffixes(structField, envValue)
+	}
+
+	return envValue, nil
+}
+
+// bindValue transforms and validates the raw value before setting it on the field. An empty value clears the field.
+func (b *binder) bindValue(field reflect.Value, structField reflect.StructField, envValue string) error {
+	if isAtomicType(field.Type()) && envValue == "" {
+		return setAtomicField(field, structField, parseOptions{}, envValue)
+	}
+
+	if isValueType(field.Type()) && envValue == "" {
+		return setValueField(field, structField, parseOptions{}, envValue)
+	}
+
+	if envValue == "" {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	opts := b.parseOptions(structField)
+	envValue, err := b.prepareValue(structField, opts, envValue)
+	if err != nil {
+		return err
+	}
+
+	// the elements of a slice are validated once they are parsed
+	if field.Kind() != reflect.Slice {
+		if envValue, err = validateOneOf(structField, envValue); err != nil {
+			return err
+		}
+	}
+
+	if b.strictNumeric {
+		if err := checkNumericFormat(field, structField, envValue); err != nil {
+			return err
+		}
+	}
+
+	if field.Kind() == reflect.Slice || field.Kind() == reflect.Map {
+		// parse into a temporary value so that the field is left unchanged if the length is invalid
+		parsed := reflect.New(field.Type()).Elem()
+		if err := setFieldValue(parsed, structField, opts, envValue); err != nil {
+			return err
+		}
+		if err := b.validateElements(parsed, structField); err != nil {
+			return err
+		}
+		if err := validateLength(parsed, structField); err != nil {
+			return err
+		}
+		field.Set(parsed)
+		return nil
+	}
+
+	if field.Kind() == reflect.String {
+		if err := validateStringLength(structField, envValue); err != nil {
+			return err
+		}
+	}
+
+	if isNumericKind(field.Kind()) && (getTag(structField, ENV_MIN_TAG) != "" || getTag(structField, ENV_MAX_TAG) != "") {
+		// parse into a temporary value so that the field is left unchanged if the value is out of range
+		parsed := reflect.New(field.Type()).Elem()
+		if err := setFieldValue(parsed, structField, opts, envValue); err != nil {
+			return err
+		}
+		if err := validateRange(parsed, structField, opts, b.clamp); err != nil {
+			return err
+		}
+		field.Set(parsed)
+		return nil
+	}
+
+	return setFieldValue(field, structField, opts, envValue)
+}
+
+// validateRange checks a numeric or duration value against the `env-min` and `env-max` tags. The bounds are parsed like
+// the field itself, so a duration field accepts bounds such as "1s". When clamp is true a value that is out of range is
+// replaced by the bound it exceeds instead of returning an error.
+func validateRange(val reflect.Value, field reflect.StructField, opts parseOptions, clamp bool) error {
+	bounds := []struct {
+		tag   string
+		below bool
+	}{
+		{tag: ENV_MIN_TAG, below: true},
+		{tag: ENV_MAX_TAG, below: false},
+	}
+
+	for _, bound := range bounds {
+		tag := getTag(field, bound.tag)
+		if tag == "" {
+			continue
+		}
+
+		limit := reflect.New(val.Type()).Elem()
+		if err := setFieldValue(limit, field, opts, tag); err != nil {
+			return fmt.Errorf("unable to set value for field %s. invalid %s tag %s: %w", field.Name, bound.tag, tag, err)
+		}
+
+		order := compareNumeric(val, limit)
+		if (bound.below && order >= 0) || (!bound.below && order <= 0) {
+			continue
+		}
+		if clamp {
+			val.Set(limit)
+			continue
+		}
+		if bound.below {
+			return fmt.Errorf("unable to set value for field %s. %v is less than the minimum %v", field.Name, val.Interface(), limit.Interface())
+		}
+		return fmt.Errorf("unable to set value for field %s. %v is greater than the maximum %v", field.Name, val.Interface(), limit.Interface())
+	}
+
+	return nil
+}
+
+// isNumericKind reports whether values of kind k can be checked with validateRange
+func isNumericKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// compareNumeric returns -1, 0 or 1 when a is less than, equal to or greater than b, which must have the same kind
+func compareNumeric(a, b reflect.Value) int {
+	switch a.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	}
+	return cmp.Compare(a.Int(), b.Int())
+}
+
+// hasLength reports whether `env-min-len` bounds the length of a field of type t
+func hasLength(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Slice || t.Kind() == reflect.Map || t.Kind() == reflect.String
+}
+
+// validateRequiredElements checks that a required slice or map has at least one element once it is bound, so that a
+// value such as "" or "[]" does not satisfy `env-required`
+func validateRequiredElements(field reflect.Value, structField reflect.StructField) error {
+	if getTag(structField, ENV_REQUIRED_TAG) != "true" {
+		return nil
+	}
+	if (field.Kind() == reflect.Slice || field.Kind() == reflect.Map) && field.Len() == 0 {
+		return fmt.Errorf("unable to set value for field %s. required %s has no elements", structField.Name, field.Kind())
+	}
+	return nil
+}
+
+// validateElements checks every element of a slice against the `env-oneof`, `env-min` and `env-max` tags of the field,
+// naming the index of the first element that fails, e.g. "unable to set value for field Features[1]. x is not one of
+// [a, b]". Elements matched with `env-oneof-fold` are stored in lowercase, and out of range elements are clamped when
+// the binder clamps. Other kinds of value, such as maps, are left unchanged.
+func (b *binder) validateElements(val reflect.Value, field reflect.StructField) error {
+	if val.Kind() != reflect.Slice || val.Type() == rawMessageType {
+		return nil
+	}
+	hasOneOf := getTag(field, ENV_ONEOF_TAG) != ""
+	hasRange := getTag(field, ENV_MIN_TAG) != "" || getTag(field, ENV_MAX_TAG) != ""
+	if !hasOneOf && !hasRange {
+		return nil
+	}
+
+	for i := 0; i < val.Len(); i++ {
+		elem := val.Index(i)
+		elemField := field
+		elemField.Name = fmt.Sprintf("%s[%d]", field.Name, i)
+
+		if hasOneOf {
+			matched, err := validateOneOf(elemField, formatFieldValue(elem, field))
+			if err != nil {
+				return err
+			}
+			if elem.Kind() == reflect.String {
+				elem.SetString(matched)
+			}
+		}
+
+		if hasRange && isNumericKind(elem.Kind()) {
+			if err := validateRange(elem, elemField, b.parseOptions(field), b.clamp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateLength checks the number of elements of a slice or map against the `env-min-len` and `env-max-len` tags
+func validateLength(val reflect.Value, field reflect.StructField) error {
+	if minTag := getTag(field, ENV_MIN_LEN_TAG); minTag != "" {
+		min, err := strconv.Atoi(minTag)
+		if err != nil {
+			return fmt.Errorf("unable to set value for field %s. invalid %s tag %s: %w", field.Name, ENV_MIN_LEN_TAG, minTag, err)
+		}
+		if val.Len() < min {
+			return fmt.Errorf("unable to set value for field %s. expected at least %d elements, got %d", field.Name, min, val.Len())
+		}
+	}
+
+	if maxTag := getTag(field, ENV_MAX_LEN_TAG); maxTag != "" {
+		max, err := strconv.Atoi(maxTag)
+		if err != nil {
+			return fmt.Errorf("unable to set value for field %s. invalid %s tag %s: %w", field.Name, ENV_MAX_LEN_TAG, maxTag, err)
+		}
+		if val.Len() > max {
+			return fmt.Errorf("unable to set value for field %s. expected at most %d elements, got %d", field.Name, max, val.Len())
+		}
+	}
+
+	return nil
+}
+
+// validateStringLength checks the number of characters of a string value against the `env-min-len` and `env-max-len`
+// tags. The value of a field tagged with `env-secret` is replaced by REDACTED_VALUE in the error, which only reports its
+// length.
+func validateStringLength(field reflect.StructField, envValue string) error {
+	length := utf8.RuneCountInString(envValue)
+	shown := envValue
+	if getTag(field, ENV_SECRET_TAG) == "true" {
+		shown = REDACTED_VALUE
+	}
+
+	if minTag := getTag(field, ENV_MIN_LEN_TAG); minTag != "" {
+		min, err := strconv.Atoi(minTag)
+		if err != nil {
+			return fmt.Errorf("unable to set value for field %s. invalid %s tag %s: %w", field.Name, ENV_MIN_LEN_TAG, minTag, err)
+		}
+		if length < min {
+			return fmt.Errorf("unable to set value for field %s. %s has %d characters, expected at least %d", field.Name, shown, length, min)
+		}
+	}
+
+	if maxTag := getTag(field, ENV_MAX_LEN_TAG); maxTag != "" {
+		max, err := strconv.Atoi(maxTag)
+		if err != nil {
+			return fmt.Errorf("unable to set value for field %s. invalid %s tag %s: %w", field.Name, ENV_MAX_LEN_TAG, maxTag, err)
+		}
+		if length > max {
+			return fmt.Errorf("unable to set value for field %s. %s has %d characters, expected at most %d", field.Name, shown, length, max)
+		}
+	}
+
+	return nil
+}
+
+// getEnvValue returns the value of the first of the field's environment variables that is set, falling back to the
+// variable named by its `env-default-from` tag and then to its default. The returned bool reports whether a value was
+// found; an empty value is only found when the field allows empty values.
+func (b *binder) getEnvValue(field reflect.StructField, prefix string) (string, bool) {
+	value, _, source := b.resolveEnvValue(field, prefix)
+	return value, source != SourceUnset
+}
+
+// resolveEnvValue is like getEnvValue but also returns the variable the value was read from, or the field's primary
+// variable when the value is a default, and where the value came from
+func (b *binder) resolveEnvValue(field reflect.StructField, prefix string) (string, string, FieldSource) {
+	names := b.envNames(field)
+	if len(names) == 0 {
+		return "", "", SourceUnset
+	}
+
+	// the first candidate name with a non-empty value wins
+	allowEmpty := getTag(field, ENV_ALLOW_EMPTY_TAG) == "true"
+	b.allowEmpty = allowEmpty
+	setEmpty := ""
+	for _, name := range names {
+		value, ok := b.lookup(prefix + name)
+		if value != "" {
+			b.allowEmpty = false
+			return value, prefix + name, SourceEnv
+		}
+		if ok && setEmpty == "" {
+			setEmpty = prefix + name
+		}
+	}
+	b.allowEmpty = false
+	if setEmpty != "" && allowEmpty {
+		return "", setEmpty, SourceEnv
+	}
+
+	if defaultFrom := getTag(field, ENV_DEFAULT_FROM_TAG); defaultFrom != "" {
+		if value, _ := b.lookup(prefix + defaultFrom); value != "" {
+			return value, prefix + defaultFrom, SourceDefaultFrom
+		}
+	}
+
+	if defaultTag := getTag(field, canonicalDefaultTag); defaultTag != "" {
+		return defaultTag, prefix + names[0], SourceDefault
+	}
 
-func validateInput(v interface{}) (reflect.Value, error) {
-	rv := reflect.ValueOf(v)
-	if rv.Kind() != reflect.Ptr || rv.IsNil() {
-		return reflect.Value{}, errors.New("provided value must be a non-nil pointer to a struct")
+	for _, name := range names {
+		if value := b.fileDefaults[prefix+name]; value != "" {
+			return value, prefix + name, SourceDefaultsFile
+		}
 	}
+	return "", prefix + names[0], SourceUnset
+}
 
-	rv = rv.Elem()
-	if rv.Kind() != reflect.Struct {
-		return reflect.Value{}, errors.New("provided value must be a pointer to a struct")
+func validateOneOf(field reflect.StructField, envValue string) (string, error) {
+	oneOfTag := getTag(field, ENV_ONEOF_TAG)
+	if oneOfTag == "" {
+		return envValue, nil
 	}
 
-	return rv, nil
-}
+	fold := getTag(field, ENV_ONEOF_FOLD_TAG) == "true"
+	if fold {
+		envValue = strings.ToLower(envValue)
+	}
 
-func setFieldValues(rv reflect.Value) error {
-	rt := rv.Type()
-	for i := 0; i < rv.NumField(); i++ {
-		field := rv.Field(i)
-		if !field.CanSet() {
-			continue
+	allowed := strings.Split(oneOfTag, ",")
+	for _, a := range allowed {
+		if fold {
+			a = strings.ToLower(a)
 		}
-
-		if field.Kind() == reflect.Struct {
-			if err := BindEnv(field.Addr().Interface()); err != nil {
-				return fmt.Errorf("unable to set value for field %s: %w", field.Type().Name(), err)
-			}
-			continue
+		if envValue == a {
+			return envValue, nil
 		}
+	}
 
-		envTag := rt.Field(i).Tag.Get(ENV_TAG)
-		if envTag == "" {
-			continue
-		}
+	return "", fmt.Errorf("unable to set value for field %s. %s is not one of [%s]", field.Name, envValue, strings.Join(allowed, ", "))
+}
 
-		envValue := getEnvValue(rt.Field(i), envTag)
-		if envValue == "" {
-			continue
-		}
+func setFieldValue(field reflect.Value, structField reflect.StructField, opts parseOptions, envValue string) error {
+	if parser, ok := getParser(field.Type()); ok {
+		return setParsedField(field, structField.Name, parser, envValue)
+	}
 
-		if err := setFieldValue(field, envValue); err != nil {
+	if field.Kind() == reflect.Ptr {
+		// allocate a new value rather than writing through the existing pointer, which may be shared
+		ptr := reflect.New(field.Type().Elem())
+		if err := setFieldValue(ptr.Elem(), structField, opts, envValue); err != nil {
 			return err
 		}
+		field.Set(ptr)
+		return nil
 	}
 
-	return nil
-}
+	if isEnvSetter(field.Type()) {
+		return setEnvSetterField(field, structField.Name, envValue)
+	}
 
-func getEnvValue(field reflect.StructField, envTag string) string {
-	envValue := os.Getenv(envTag)
-	if envValue == "" {
-		defaultTag := field.Tag.Get(ENV_DEFAULT_TAG)
-		if defaultTag != "" {
-			envValue = defaultTag
-		}
+	if isFlagValue(field.Type()) {
+		return setFlagValueField(field, structField.Name, envValue)
+	}
+
+	if field.Type() == timeType {
+		return setTimeField(field, structField.Name, getTag(structField, ENV_LAYOUT_TAG), envValue)
+	}
+
+	if isAtomicType(field.Type()) {
+		return setAtomicField(field, structField, opts, envValue)
+	}
+
+	if isValueType(field.Type()) {
+		return setValueField(field, structField, opts, envValue)
+	}
+
+	if field.Type() == certificateType {
+		return setCertificateField(field, structField.Name, envValue)
+	}
+
+	if field.Type() == urlType {
+		return setURLField(field, structField.Name, envValue)
+	}
+
+	if isTextUnmarshaler(field.Type()) {
+		return setTextField(field, structField.Name, envValue)
+	}
+
+	if field.Type() == bytesType && getTag(structField, ENV_FORMAT_TAG) == "pem" {
+		return setPEMBytesField(field, structField.Name, envValue)
+	}
+
+	if field.Type() == rawMessageType {
+		return setRawMessageField(field, structField, envValue)
+	}
+
+	if encoding := getTag(structField, ENV_ENCODING_TAG); encoding != "" && field.Type() == bytesType {
+		return setEncodedBytesField(field, structField.Name, encoding, envValue)
+	}
+
+	if field.Kind() == reflect.Slice {
+		return setSliceField(field, structField, opts, envValue)
+	}
+
+	if field.Kind() == reflect.Map {
+		return setMapField(field, structField, opts, envValue)
+	}
+
+	if field.Kind() == reflect.Interface && field.NumMethod() == 0 {
+		return setInterfaceField(field, structField, envValue)
+	}
+
+	if unit := getTag(structField, ENV_UNIT_TAG); unit != "" {
+		return setUnitField(field, structField.Name, unit, envValue)
+	}
+
+	if field.Type() == durationType {
+		return setDurationField(field, structField.Name, getTag(structField, ENV_DURATION_FORMAT_TAG), envValue)
+	}
+
+	if opts.digitSeparators != "" && isNumericKind(field.Kind()) {
+		envValue = stripDigitSeparators(envValue, opts.digitSeparators)
 	}
-	return envValue
-}
 
-func setFieldValue(field reflect.Value, envValue string) error {
 	switch field.Kind() {
 	case reflect.String:
 		field.SetString(envValue)
-	case reflect.Int:
-		return setIntField(field, envValue)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		base, err := getBase(structField)
+		if err != nil {
+			return err
+		}
+		return setIntField(field, structField.Name, base, envValue)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		base, err := getBase(structField)
+		if err != nil {
+			return err
+		}
+		return setUintField(field, structField.Name, base, envValue)
 	case reflect.Bool:
-		return setBoolField(field, envValue)
+		return setBoolField(field, structField.Name, opts.boolMode, envValue)
 	case reflect.Float64:
-		return setFloat64Field(field, envValue)
-	case reflect.Slice:
-		return setSliceField(field, envValue)
+		return setFloat64Field(field, structField.Name, envValue)
+	}
+	return nil
+}
+
+// stripDigitSeparators removes every character of separators from the value, so that "1_000_000" parses as 1000000
+func stripDigitSeparators(envValue string, separators string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(separators, r) {
+			return -1
+		}
+		return r
+	}, envValue)
+}
+
+func setIntField(field reflect.Value, name string, base int, envValue string) error {
+	val, err := strconv.ParseInt(envValue, base, field.Type().Bits())
+	if err != nil {
+		return &ParseError{Name: name, Value: envValue, Kind: field.Kind().String(), Err: err}
+	}
+	field.SetInt(val)
+	return nil
+}
+
+func setUintField(field reflect.Value, name string, base int, envValue string) error {
+	val, err := strconv.ParseUint(envValue, base, field.Type().Bits())
+	if err != nil {
+		return &ParseError{Name: name, Value: envValue, Kind: field.Kind().String(), Err: err}
 	}
+	field.SetUint(val)
 	return nil
 }
 
-func setIntField(field reflect.Value, envValue string) error {
-	val, err := strconv.Atoi(envValue)
+func setDurationField(field reflect.Value, name string, format string, envValue string) error {
+	switch format {
+	case "", "go", "iso8601":
+	default:
+		return fmt.Errorf("unable to set value for field %s. invalid %s tag %s", name, ENV_DURATION_FORMAT_TAG, format)
+	}
+
+	val, err := parseDuration(format, envValue)
 	if err != nil {
-		return fmt.Errorf("unable to set value for field %s. failed to parse %s as int: %w", field.Type().Name(), envValue, err)
+		return &ParseError{Name: name, Value: envValue, Kind: "duration", Err: err}
 	}
 	field.SetInt(int64(val))
 	return nil
 }
 
-func setBoolField(field reflect.Value, envValue string) error {
-	val, err := strconv.ParseBool(envValue)
+// getBase returns the base used to parse an integer field, from its `env-base` tag. A base of 0 detects the base from
+// the prefix of the value, e.g. 0x for hexadecimal.
+func getBase(field reflect.StructField) (int, error) {
+	baseTag := getTag(field, ENV_BASE_TAG)
+	if baseTag == "" {
+		return 10, nil
+	}
+
+	base, err := strconv.Atoi(baseTag)
+	if err != nil || base == 1 || base < 0 || base > 36 {
+		return 0, fmt.Errorf("unable to set value for field %s. invalid %s tag %s", field.Name, ENV_BASE_TAG, baseTag)
+	}
+	return base, nil
+}
+
+func setBoolField(field reflect.Value, name string, mode string, envValue string) error {
+	var val bool
+	var err error
+	switch mode {
+	case "", "standard":
+		val, err = strconv.ParseBool(strings.TrimSpace(envValue))
+	case "extended":
+		val, err = parseExtendedBool(envValue)
+	case "numeric":
+		val, err = parseNumericBool(envValue)
+	default:
+		return fmt.Errorf("unable to set value for field %s. invalid %s tag %s", name, ENV_BOOL_MODE_TAG, mode)
+	}
 	if err != nil {
-		return fmt.Errorf("unable to set value for field %s. failed to parse %s as bool: %w", field.Type().Name(), envValue, err)
+		return &ParseError{Name: name, Value: envValue, Kind: "bool", Err: err}
 	}
 	field.SetBool(val)
 	return nil
 }
 
-func setFloat64Field(field reflect.Value, envValue string) error {
+// extendedBools maps the lowercase tokens accepted by the extended bool mode to their values. Besides the tokens of
+// strconv.ParseBool, they cover the words emitted by Kubernetes, CI systems and feature flag tooling.
+var extendedBools = map[string]bool{
+	"1": true, "t": true, "true": true, "y": true, "yes": true, "on": true, "enable": true, "enabled": true,
+	"0": false, "f": false, "false": false, "n": false, "no": false, "off": false, "disable": false, "disabled": false,
+}
+
+// parseExtendedBool parses a bool from any of the extendedBools tokens, ignoring case and surrounding whitespace
+func parseExtendedBool(envValue string) (bool, error) {
+	val, ok := extendedBools[strings.ToLower(strings.TrimSpace(envValue))]
+	if !ok {
+		return false, fmt.Errorf("unknown bool token %q", envValue)
+	}
+	return val, nil
+}
+
+// parseNumericBool parses a base 10 integer of any size, ignoring surrounding whitespace, and reports whether it is
+// nonzero
+func parseNumericBool(envValue string) (bool, error) {
+	val, err := strconv.ParseInt(strings.TrimSpace(envValue), 10, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		// the value is a valid integer too large for int64, so it cannot be zero
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return val != 0, nil
+}
+
+func setFloat64Field(field reflect.Value, name string, envValue string) error {
 	val, err := strconv.ParseFloat(envValue, 64)
 	if err != nil {
-		return fmt.Errorf("unable to set value for field %s. failed to parse %s as float64: %w", field.Type().Name(), envValue, err)
+		return &ParseError{Name: name, Value: envValue, Kind: "float64", Err: err}
 	}
 	field.SetFloat(val)
 	return nil
 }
 
-func setSliceField(field reflect.Value, envValue string) error {
-	split := strings.Split(envValue, ",")
-	switch field.Type().Elem().Kind() {
-	case reflect.String:
-		field.Set(reflect.ValueOf(split))
-	case reflect.Bool:
-		return setBoolSlice(field, split)
-	case reflect.Float64:
-		return setFloat64Slice(field, split)
-	case reflect.Int:
-		return setIntSlice(field, split)
+func setTimeField(field reflect.Value, name string, layout string, envValue string) error {
+	var val time.Time
+	switch layout {
+	case "unix", "unixmilli":
+		epoch, err := strconv.ParseInt(envValue, 10, 64)
+		if err != nil {
+			return &ParseError{Name: name, Value: envValue, Kind: layout + " timestamp", Err: err}
+		}
+		if layout == "unix" {
+			val = time.Unix(epoch, 0)
+		} else {
+			val = time.UnixMilli(epoch)
+		}
+	default:
+		if layout == "" {
+			layout = DEFAULT_TIME_LAYOUT
+		}
+		var err error
+		val, err = time.Parse(layout, envValue)
+		if err != nil {
+			return &ParseError{Name: name, Value: envValue, Kind: "time", Err: err}
+		}
+	}
+	field.Set(reflect.ValueOf(val))
+	return nil
+}
+
+// setSliceField splits the value and sets each element with setFieldValue, so slices support every type that a scalar
+// field does
+func setSliceField(field reflect.Value, structField reflect.StructField, opts parseOptions, envValue string) error {
+	format := getTag(structField, ENV_FORMAT_TAG)
+	if format == "json" || strings.HasPrefix(strings.TrimSpace(envValue), "[") {
+		// the affixes are removed from the elements of an array of strings before they are parsed
+		var elems []string
+		if hasAffixTrim(structField) && json.Unmarshal([]byte(envValue), &elems) == nil {
+			return setSliceElements(field, structField, opts, elems)
+		}
+
+		slice := reflect.New(field.Type())
+		err := json.Unmarshal([]byte(envValue), slice.Interface())
+		if err == nil {
+			field.Set(slice.Elem())
+			return nil
+		}
+		// an array of strings whose elements the JSON decoder rejected, such as an invalid CIDR in a []netip.Prefix, is
+		// parsed element by element so that the error names the failing element
+		if json.Unmarshal([]byte(envValue), &elems) == nil {
+			return setSliceElements(field, structField, opts, elems)
+		}
+		if format == "json" {
+			return &ParseError{Name: structField.Name, Value: envValue, Kind: "json", Err: err}
+		}
+		// the value only looked like JSON, so split it as usual
+	}
+
+	split, err := splitSliceValue(structField, envValue)
+	if err != nil {
+		return &ParseError{Name: structField.Name, Value: envValue, Kind: "csv", Err: err}
+	}
+
+	if getTag(structField, ENV_SKIP_EMPTY_TAG) == "true" {
+		nonEmpty := split[:0]
+		for _, elem := range split {
+			if strings.TrimSpace(elem) != "" {
+				nonEmpty = append(nonEmpty, elem)
+			}
+		}
+		split = nonEmpty
+	}
+
+	return setSliceElements(field, structField, opts, split)
+}
+
+// setInterfaceField decodes a JSON value into an empty interface field. A value that is not valid JSON is stored as a
+// string, unless the field requires JSON with `env-format:"json"`.
+func setInterfaceField(field reflect.Value, structField reflect.StructField, envValue string) error {
+	var val interface{}
+	if err := json.Unmarshal([]byte(envValue), &val); err != nil {
+		if getTag(structField, ENV_FORMAT_TAG) == "json" {
+			return &ParseError{Name: structField.Name, Value: envValue, Kind: "json", Err: err}
+		}
+		val = envValue
+	}
+
+	if val == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	field.Set(reflect.ValueOf(val))
+	return nil
+}
+
+// unquoteValue strips a matching pair of single or double quotes surrounding the value. Values with unbalanced quotes
+// are left untouched, as are values such as 'a','b' where the quote also appears inside, so that the elements of a
+// slice can be unquoted individually.
+func unquoteValue(value string) string {
+	if len(value) < 2 || (value[0] != '"' && value[0] != '\'') || value[len(value)-1] != value[0] {
+		return value
+	}
+
+	inner := value[1 : len(value)-1]
+	if strings.IndexByte(inner, value[0]) >= 0 {
+		return value
+	}
+	return inner
+}
+
+func setSliceElements(field reflect.Value, structField reflect.StructField, opts parseOptions, split []string) error {
+	slice := reflect.MakeSlice(field.Type(), len(split), len(split))
+	elemType := field.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	// the text types of net and net/netip never contain surrounding spaces, so lists like "10.0.0.0/8, ::1/128" parse.
+	// Other text types receive their elements as they are.
+	trim := isNetTextType(elemType)
+	for i, str := range split {
+		if trim {
+			str = strings.TrimSpace(str)
+		}
+		if opts.unquote {
+			str = unquoteValue(str)
+		}
+		str = trimAffixes(structField, str)
+		if err := setFieldValue(slice.Index(i), structField, opts, str); err != nil {
+			return elementError(structField.Name, i, err)
+		}
 	}
+	field.Set(slice)
 	return nil
 }
 
-func setBoolSlice(field reflect.Value, split []string) error {
-	boolSlice := make([]bool, 0, len(split))
-	for _, str := range split {
-		val, err := strconv.ParseBool(str)
+// setMapField sets a map from separated key=value pairs, or decodes a JSON object when the field is tagged with
+// `env-format:"json"`. When the values of the map are slices, each value is split with the `env-value-separator` tag,
+// so `k=a|b,j=c` binds to map[string][]string{"k": {"a", "b"}, "j": {"c"}}.
+func setMapField(field reflect.Value, structField reflect.StructField, opts parseOptions, envValue string) error {
+	if getTag(structField, ENV_FORMAT_TAG) == "json" {
+		m := reflect.New(field.Type())
+		if err := json.Unmarshal([]byte(envValue), m.Interface()); err != nil {
+			return &ParseError{Name: structField.Name, Value: envValue, Kind: "json", Err: err}
+		}
+		field.Set(m.Elem())
+		return nil
+	}
+
+	pairs, err := splitSliceValue(structField, envValue)
+	if err != nil {
+		return &ParseError{Name: structField.Name, Value: envValue, Kind: "csv", Err: err}
+	}
+
+	mapType := field.Type()
+	m := reflect.MakeMapWithSize(mapType, len(pairs))
+	for _, pair := range pairs {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("unable to set value for field %s. malformed pair %s, expected key=value", structField.Name, pair)
+		}
+		if opts.unquote {
+			k, v = unquoteValue(k), unquoteValue(v)
+		}
+
+		key := reflect.New(mapType.Key()).Elem()
+		if err := setFieldValue(key, structField, opts, k); err != nil {
+			return err
+		}
+
+		// a slice type with a registered parser is parsed from the whole value like any other type with a parser
+		value := reflect.New(mapType.Elem()).Elem()
+		if mapType.Elem().Kind() == reflect.Slice && !hasParser(mapType.Elem()) {
+			err = setSliceElements(value, structField, opts, strings.Split(v, getValueSeparator(structField)))
+		} else {
+			err = setFieldValue(value, structField, opts, trimAffixes(structField, v))
+		}
 		if err != nil {
-			return fmt.Errorf("unable to set value for field %s. failed to parse %s as bool: %w", field.Type().Name(), str, err)
+			return err
 		}
-		boolSlice = append(boolSlice, val)
+
+		m.SetMapIndex(key, value)
 	}
-	field.Set(reflect.ValueOf(boolSlice))
+	field.Set(m)
 	return nil
 }
 
-func setFloat64Slice(field reflect.Value, split []string) error {
-	floatSlice := make([]float64, 0, len(split))
-	for _, str := range split {
-		val, err := strconv.ParseFloat(str, 64)
+// getValueSeparator returns the separator used to split the slice values of a map field
+func getValueSeparator(field reflect.StructField) string {
+	separator := getTag(field, ENV_VALUE_SEPARATOR_TAG)
+	if separator == "" {
+		return DEFAULT_VALUE_SEPARATOR
+	}
+
+	if decoded, err := strconv.Unquote(`"` + separator + `"`); err == nil {
+		return decoded
+	}
+	return separator
+}
+
+func splitSliceValue(field reflect.StructField, envValue string) ([]string, error) {
+	separator := getSeparator(field)
+	if getTag(field, ENV_QUOTED_TAG) != "true" {
+		return strings.Split(envValue, separator), nil
+	}
+
+	comma, size := utf8.DecodeRuneInString(separator)
+	if size != len(separator) {
+		return nil, fmt.Errorf("separator %q must be a single character to split quoted values", separator)
+	}
+
+	r := csv.NewReader(strings.NewReader(envValue))
+	r.Comma = comma
+	return r.Read()
+}
+
+// getSeparator returns the separator used to split a slice field. Escape sequences such as \n and \t in the
+// `env-separator` tag are decoded so that whitespace can be used as a separator.
+func getSeparator(field reflect.StructField) string {
+	separator := getTag(field, ENV_SEPARATOR_TAG)
+	if separator == "" {
+		return DEFAULT_SEPARATOR
+	}
+
+	if decoded, err := strconv.Unquote(`"` + separator + `"`); err == nil {
+		return decoded
+	}
+	return separator
+}
+
+// isSupportedType reports whether setFieldValue can set a field of type t
+func isSupportedType(t reflect.Type) bool {
+	switch {
+	case hasParser(t), isFlagValue(t), isTextUnmarshaler(t), isEnvSetter(t), t == timeType, t == durationType, t == certificateType, t == urlType:
+		return true
+	case isAtomicType(t):
+		store, ok := reflect.PointerTo(t).MethodByName("Store")
+		if !ok {
+			return false
+		}
+		switch store.Type.In(1).Kind() {
+		case reflect.Int32, reflect.Int64, reflect.Uint32, reflect.Uint64, reflect.Uintptr, reflect.Bool:
+			return true
+		}
+		return false
+	case isValueType(t):
+		return isSupportedType(reflect.New(t).Interface().(valueStorer).valueType())
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Ptr, reflect.Slice:
+		return isSupportedType(t.Elem())
+	case reflect.Map:
+		return isSupportedType(t.Key()) && isSupportedType(t.Elem())
+	case reflect.Interface:
+		return t.NumMethod() == 0
+	}
+	return false
+}
+
+// isNestedStruct reports whether t is a struct whose fields should be bound individually, rather than a struct type
+// such as time.Time that is bound from a single value
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && t != certificateType && t != urlType && !isAtomicType(t) && !isValueType(t) &&
+		!isSelfBinder(t) && !hasParser(t) && !isFlagValue(t) && !isTextUnmarshaler(t) && !isEnvSetter(t)
+}
+
+// elementError names the index of the element that failed to parse in the error returned for it, and describes an
+// element that does not fit the size of the element type, such as 70000 in a []int16
+func elementError(name string, index int, err error) error {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		elemErr := *parseErr
+		elemErr.Name = fmt.Sprintf("%s[%d]", name, index)
+		if errors.Is(parseErr.Err, strconv.ErrRange) {
+			elemErr.Err = fmt.Errorf("element %s overflows %s at index %d: %w", parseErr.Value, parseErr.Kind, index, strconv.ErrRange)
+		}
+		return &elemErr
+	}
+	return fmt.Errorf("unable to set value for field %s[%d]: %w", name, index, err)
+}
+
+// isStructSlice reports whether t is a slice of structs, or of pointers to structs, that are bound field by field
+func isStructSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && isNestedStruct(structElemType(t))
+}
+
+// structElemType returns the struct type of the elements of a slice or map, dereferencing pointer elements
+func structElemType(t reflect.Type) reflect.Type {
+	if t.Elem().Kind() == reflect.Ptr {
+		return t.Elem().Elem()
+	}
+	return t.Elem()
+}
+
+// newStructElem allocates an element for a slice or map of type t, returning the element and the struct to bind, which
+// differ when the elements are pointers
+func newStructElem(t reflect.Type) (reflect.Value, reflect.Value) {
+	if t.Elem().Kind() == reflect.Ptr {
+		ptr := reflect.New(t.Elem().Elem())
+		return ptr, ptr.Elem()
+	}
+	elem := reflect.New(t.Elem()).Elem()
+	return elem, elem
+}
+
+// setStructSliceField binds each element of a slice of structs from environment variables of the form
+// KEY_<index>_<FIELD>. Indices are discovered contiguously from zero, stopping at the first index with no variables.
+func (b *binder) setStructSliceField(field reflect.Value, key string) error {
+	slice := reflect.MakeSlice(field.Type(), 0, 0)
+	var errs []error
+	for i := 0; ; i++ {
+		elemPrefix := fmt.Sprintf("%s_%d_", key, i)
+		if !b.hasEnvPrefix(elemPrefix) {
+			break
+		}
+
+		elem, target := newStructElem(field.Type())
+		restorePath := b.enterPath(fmt.Sprintf("[%d].", i))
+		restoreTracking := b.untracked()
+		err := b.setFieldValues(target, elemPrefix)
+		restoreTracking()
+		restorePath()
 		if err != nil {
-			return fmt.Errorf("unable to set value for field %s. failed to parse %s as float64: %w", field.Type().Name(), str, err)
+			if err := b.fail(&errs, fmt.Errorf("failed to bind element %d: %w", i, err)); err != nil {
+				return err
+			}
 		}
-		floatSlice = append(floatSlice, val)
+		slice = reflect.Append(slice, elem)
+	}
+	// with Collect, the field keeps its previous value when any element failed
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if slice.Len() > 0 {
+		field.Set(slice)
+	} else if b.clearOnUnset {
+		field.Set(reflect.Zero(field.Type()))
 	}
-	field.Set(reflect.ValueOf(floatSlice))
 	return nil
 }
 
-func setIntSlice(field reflect.Value, split []string) error {
-	intSlice := make([]int, 0, len(split))
-	for _, str := range split {
-		val, err := strconv.Atoi(str)
+// isStructMap reports whether t is a map from strings to structs that are bound field by field
+func isStructMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && isNestedStruct(structElemType(t))
+}
+
+// setStructMapField binds each element of a map of structs from environment variables of the form
+// KEY_<name>_<FIELD>. The names are discovered from the variables that end in the name of a field of the struct.
+func (b *binder) setStructMapField(field reflect.Value, key string) error {
+	names := b.structMapNames(structElemType(field.Type()), key+"_")
+	if len(names) == 0 {
+		if b.clearOnUnset {
+			field.Set(reflect.Zero(field.Type()))
+		}
+		return nil
+	}
+
+	m := reflect.MakeMapWithSize(field.Type(), len(names))
+	var errs []error
+	for _, name := range names {
+		elem, target := newStructElem(field.Type())
+		restorePath := b.enterPath("[" + name + "].")
+		restoreTracking := b.untracked()
+		err := b.setFieldValues(target, key+"_"+name+"_")
+		restoreTracking()
+		restorePath()
 		if err != nil {
-			return fmt.Errorf("unable to set value for field %s. failed to parse %s as int: %w", field.Type().Name(), str, err)
+			if err := b.fail(&errs, fmt.Errorf("failed to bind %s: %w", name, err)); err != nil {
+				return err
+			}
 		}
-		intSlice = append(intSlice, val)
+		m.SetMapIndex(reflect.ValueOf(name).Convert(field.Type().Key()), elem)
+	}
+	// with Collect, the field keeps its previous value when any element failed
+	if len(errs) > 0 {
+		return errors.Join(errs...)
 	}
-	field.Set(reflect.ValueOf(intSlice))
+	field.Set(m)
 	return nil
 }
 
+// structMapNames returns the sorted names of the elements of a map of structs of type rt whose variables start with
+// prefix. A variable PREFIX<name>_<FIELD> names an element when <FIELD> is the name of a field of the struct.
+func (b *binder) structMapNames(rt reflect.Type, prefix string) []string {
+	keys := b.fieldKeys(rt, "")
+	seen := map[string]bool{}
+	var names []string
+	for _, kv := range b.environ() {
+		envKey, _, _ := strings.Cut(kv, "=")
+		rest, ok := strings.CutPrefix(envKey, b.transformKey(prefix))
+		if !ok {
+			continue
+		}
+		for _, key := range keys {
+			name, ok := strings.CutSuffix(rest, "_"+key)
+			if ok && name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fieldKeys returns every variable name that the fields of rt may be bound from, relative to prefix
+func (b *binder) fieldKeys(rt reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < rt.NumField(); i++ {
+		structField := b.field(rt, i)
+		if !structField.IsExported() {
+			continue
+		}
+		if isNestedStruct(structField.Type) {
+			keys = append(keys, b.fieldKeys(structField.Type, b.nestedPrefix(structField, prefix))...)
+			continue
+		}
+		if embedded, ok := embeddedStructType(structField); ok {
+			keys = append(keys, b.fieldKeys(embedded, b.nestedPrefix(structField, prefix))...)
+			continue
+		}
+		for _, name := range b.envNames(structField) {
+			keys = append(keys, prefix+name)
+		}
+	}
+	return keys
+}
+
+func (b *binder) hasEnvPrefix(prefix string) bool {
+	prefix = b.transformKey(prefix)
+	for _, kv := range b.environ() {
+		if strings.HasPrefix(kv, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // BindEnvWithAutoRefresh sets the values of the provided struct based on the values of the environment variables
 // defined in the struct's tags. The struct must be a non-nil pointer to a struct. This function also refreshes the
 // environment variables on a interval set with `AUTO_REFRESH_INTERVAL`.
 // v: a non-nil pointer to a struct
+// opts: the options to apply to the initial bind and every refresh
 // returns: an error if the provided value is not a non-nil pointer to a struct or if the value of an environment variable
-func BindEnvWithAutoRefresh(v interface{}) error {
-	if err := BindEnv(v); err != nil {
+func BindEnvWithAutoRefresh(v interface{}, opts ...Option) error {
+	rv, err := validateInput(v)
+	if err != nil {
+		return err
+	}
+
+	b := newBinder(opts...)
+	b.trackChanges()
+	if err := b.bind(rv); err != nil {
 		return err
 	}
 
-	refresh(AUTO_REFRESH_INTERVAL, v)
+	b.refresh(AUTO_REFRESH_INTERVAL, rv)
 
 	return nil
 }
 
+// refreshMu is held while the auto-refresh loop rebinds a struct so that Snapshot can take a consistent copy
+var refreshMu sync.RWMutex
+
 // refresh refreshes the environment variables
-func refresh(interval int, v interface{}) {
+func (b *binder) refresh(interval int, rv reflect.Value) {
 	go func() {
+		var reporter refreshReporter
 		for {
 			// sleep for the interval
 			<-time.After(time.Duration(interval) * time.Second)
-			if err := BindEnv(v); err != nil {
-				fmt.Printf("failed to refresh environment variables: %s", err)
+			refreshMu.Lock()
+			err := b.rebind(rv)
+			refreshMu.Unlock()
+			stop := b.reportRefresh(&reporter, err)
+			if b.onRefresh != nil {
+				b.onRefresh()
+			}
+			if stop {
+				return
 			}
 		}
 	}()
 }
+
+// refreshReporter tracks refresh failures so that a persistent error is not reported on every cycle
+type refreshReporter struct {
+	// lastErr is the message of the last failure, or empty if the last refresh succeeded
+	lastErr string
+	// skipped is the number of times lastErr has repeated since it was last reported
+	skipped int
+	// failures is the number of consecutive failed refreshes
+	failures int
+}
+
+// reportRefresh reports the result of a refresh. A failure is reported when its message differs from the previous
+// failure, and otherwise only once every refreshErrorInterval cycles. The first success after a failure is reported as
+// a recovery. Reports are printed only when there is no error handler and the binder is not silent. It returns true
+// when the refresh loop must stop because maxRefreshErrors consecutive refreshes failed, after reporting a terminal
+// error wrapping ErrRefreshStopped and the last failure.
+func (b *binder) reportRefresh(r *refreshReporter, err error) bool {
+	if err == nil {
+		if r.lastErr != "" && b.onError == nil && !b.silentRefresh {
+			fmt.Printf("environment variables refreshed successfully after failing with: %s\n", r.lastErr)
+		}
+		r.lastErr = ""
+		r.skipped = 0
+		r.failures = 0
+		return false
+	}
+
+	r.failures++
+	if b.maxRefreshErrors > 0 && r.failures >= b.maxRefreshErrors {
+		b.reportRefreshError(fmt.Errorf("%w after %d consecutive failures: %w", ErrRefreshStopped, r.failures, err))
+		return true
+	}
+
+	if err.Error() == r.lastErr && r.skipped+1 < b.refreshErrorInterval {
+		r.skipped++
+		return false
+	}
+	r.lastErr = err.Error()
+	r.skipped = 0
+	b.reportRefreshError(err)
+	return false
+}
+
+// reportRefreshError passes a refresh failure to the error handler, or prints it when there is none and the binder is
+// not silent
+func (b *binder) reportRefreshError(err error) {
+	if b.onError != nil {
+		b.onError(err)
+	} else if !b.silentRefresh {
+		fmt.Printf("failed to refresh environment variables: %s\n", err)
+	}
+}
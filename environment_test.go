@@ -1,8 +1,11 @@
 package ectoenv
 
 import (
+	"errors"
 	"os"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -183,6 +186,1667 @@ func TestBindEnvWithInvalidValues(t *testing.T) {
 	}
 }
 
+func TestBindEnvTime(t *testing.T) {
+	type Config struct {
+		Default   time.Time `env:"TEST_TIME_DEFAULT"`
+		Layout    time.Time `env:"TEST_TIME_LAYOUT" env-layout:"2006-01-02"`
+		Unix      time.Time `env:"TEST_TIME_UNIX" env-layout:"unix"`
+		UnixMilli time.Time `env:"TEST_TIME_UNIXMILLI" env-layout:"unixmilli"`
+	}
+
+	envVars := map[string]string{
+		"TEST_TIME_DEFAULT":   "2023-11-14T22:13:20Z",
+		"TEST_TIME_LAYOUT":    "2023-11-14",
+		"TEST_TIME_UNIX":      "1700000000",
+		"TEST_TIME_UNIXMILLI": "1700000000123",
+	}
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	var config Config
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+
+	if want := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC); !config.Default.Equal(want) {
+		t.Errorf("Default got = %v, want %v", config.Default, want)
+	}
+	if want := time.Date(2023, 11, 14, 0, 0, 0, 0, time.UTC); !config.Layout.Equal(want) {
+		t.Errorf("Layout got = %v, want %v", config.Layout, want)
+	}
+	if want := time.Unix(1700000000, 0); !config.Unix.Equal(want) {
+		t.Errorf("Unix got = %v, want %v", config.Unix, want)
+	}
+	if want := time.UnixMilli(1700000000123); !config.UnixMilli.Equal(want) {
+		t.Errorf("UnixMilli got = %v, want %v", config.UnixMilli, want)
+	}
+
+	os.Setenv("TEST_TIME_UNIX", "not_a_timestamp")
+	if err := BindEnv(&config); err == nil {
+		t.Errorf("BindEnv() expected error for non-integer unix timestamp, got nil")
+	}
+}
+
+func TestBindEnvOneOf(t *testing.T) {
+	type Config struct {
+		Exact string `env:"TEST_ONEOF_EXACT" env-oneof:"debug,info,warn"`
+		Fold  string `env:"TEST_ONEOF_FOLD" env-oneof:"debug,info,warn" env-oneof-fold:"true"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected Config
+		wantErr  bool
+	}{
+		{
+			name:     "Allowed values",
+			envVars:  map[string]string{"TEST_ONEOF_EXACT": "info", "TEST_ONEOF_FOLD": "warn"},
+			expected: Config{Exact: "info", Fold: "warn"},
+		},
+		{
+			name:     "Case folded value is normalized",
+			envVars:  map[string]string{"TEST_ONEOF_FOLD": "INFO"},
+			expected: Config{Fold: "info"},
+		},
+		{
+			name:    "Exact match is case sensitive",
+			envVars: map[string]string{"TEST_ONEOF_EXACT": "INFO"},
+			wantErr: true,
+		},
+		{
+			name:    "Value not allowed",
+			envVars: map[string]string{"TEST_ONEOF_FOLD": "trace"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.envVars {
+				os.Setenv(k, v)
+			}
+			defer func() {
+				for k := range tt.envVars {
+					os.Unsetenv(k)
+				}
+			}()
+
+			var config Config
+			err := BindEnv(&config)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("BindEnv() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnv() error = %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("BindEnv() got = %v, want %v", config, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvSliceElementValidation(t *testing.T) {
+	type Config struct {
+		Features []string        `env:"FEATURES" env-oneof:"a,b,c"`
+		Levels   []string        `env:"LEVELS" env-oneof:"debug,info" env-oneof-fold:"true"`
+		Ports    []int           `env:"PORTS" env-min:"1024" env-max:"65535"`
+		Delays   []time.Duration `env:"DELAYS" env-max:"1m"`
+		Shards   []int           `env:"SHARD" env-indexed:"true" env-oneof:"1,2,4"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		clamp    bool
+		expected Config
+		wantErr  string
+	}{
+		{
+			name:     "Valid",
+			envVars:  map[string]string{"FEATURES": "a,c", "LEVELS": "DEBUG,Info", "PORTS": "8080,9090", "DELAYS": "1s,30s", "SHARD_0": "2", "SHARD_1": "4"},
+			expected: Config{Features: []string{"a", "c"}, Levels: []string{"debug", "info"}, Ports: []int{8080, 9090}, Delays: []time.Duration{time.Second, 30 * time.Second}, Shards: []int{2, 4}},
+		},
+		{
+			name:     "JSON array",
+			envVars:  map[string]string{"FEATURES": `["b"]`},
+			expected: Config{Features: []string{"b"}},
+		},
+		{
+			name:    "Element not allowed",
+			envVars: map[string]string{"FEATURES": "a,x"},
+			wantErr: "unable to set value for field Features[1]. x is not one of [a, b, c]",
+		},
+		{
+			name:    "Element below minimum",
+			envVars: map[string]string{"PORTS": "8080,80"},
+			wantErr: "unable to set value for field Ports[1]. 80 is less than the minimum 1024",
+		},
+		{
+			name:    "Element above maximum",
+			envVars: map[string]string{"DELAYS": "2m"},
+			wantErr: "unable to set value for field Delays[0]. 2m0s is greater than the maximum 1m0s",
+		},
+		{
+			name:    "Indexed element not allowed",
+			envVars: map[string]string{"SHARD_0": "2", "SHARD_1": "3"},
+			wantErr: "unable to set value for field Shards[1]. 3 is not one of [1, 2, 4]",
+		},
+		{
+			name:     "Clamped elements",
+			envVars:  map[string]string{"PORTS": "80,70000"},
+			clamp:    true,
+			expected: Config{Ports: []int{1024, 65535}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvWith(&config, withLookupMap(tt.envVars), WithClamp(tt.clamp))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvWith() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvWith() error = %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("BindEnvWith() got = %+v, want %+v", config, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvQuotedSlice(t *testing.T) {
+	type Config struct {
+		Naive  []string `env:"TEST_QUOTED_SLICE"`
+		Quoted []string `env:"TEST_QUOTED_SLICE" env-quoted:"true"`
+	}
+
+	os.Setenv("TEST_QUOTED_SLICE", `"a,b",c`)
+	defer os.Unsetenv("TEST_QUOTED_SLICE")
+
+	var config Config
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+
+	expected := Config{
+		Naive:  []string{`"a`, `b"`, "c"},
+		Quoted: []string{"a,b", "c"},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnv() got = %v, want %v", config, expected)
+	}
+
+	os.Setenv("TEST_QUOTED_SLICE", `"a,b`)
+	if err := BindEnv(&config); err == nil {
+		t.Errorf("BindEnv() expected error for unterminated quote, got nil")
+	}
+}
+
+func TestBindEnvStructSlice(t *testing.T) {
+	type Upstream struct {
+		URL    string `env:"URL"`
+		Weight int    `env:"WEIGHT" env-default:"1"`
+	}
+	type Config struct {
+		Upstreams []Upstream `env:"TEST_UPSTREAM"`
+	}
+
+	envVars := map[string]string{
+		"TEST_UPSTREAM_0_URL":    "http://a",
+		"TEST_UPSTREAM_1_URL":    "http://b",
+		"TEST_UPSTREAM_1_WEIGHT": "5",
+		"TEST_UPSTREAM_3_URL":    "http://d",
+	}
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	var config Config
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+
+	expected := Config{
+		Upstreams: []Upstream{
+			{URL: "http://a", Weight: 1},
+			{URL: "http://b", Weight: 5},
+		},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnv() got = %v, want %v", config, expected)
+	}
+
+	os.Setenv("TEST_UPSTREAM_1_WEIGHT", "heavy")
+	if err := BindEnv(&config); err == nil {
+		t.Errorf("BindEnv() expected error for invalid element value, got nil")
+	}
+}
+
+func TestBindEnvPointerSlices(t *testing.T) {
+	type Endpoint struct {
+		URL string `env:"URL"`
+	}
+	type Config struct {
+		Ports     []*int      `env:"PORTS"`
+		Endpoints []*Endpoint `env:"ENDPOINT"`
+	}
+
+	var config Config
+	err := BindEnvFromMap(&config, map[string]string{
+		"PORTS":          "80,443",
+		"ENDPOINT_0_URL": "http://a",
+		"ENDPOINT_1_URL": "http://b",
+	})
+	if err != nil {
+		t.Fatalf("BindEnvFromMap() error = %v", err)
+	}
+
+	port80, port443 := 80, 443
+	expected := Config{
+		Ports:     []*int{&port80, &port443},
+		Endpoints: []*Endpoint{{URL: "http://a"}, {URL: "http://b"}},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvFromMap() got = %v, want %v", config, expected)
+	}
+
+	err = BindEnvFromMap(&config, map[string]string{"PORTS": "80,https"})
+	want := `unable to set value for field Ports[1]. failed to parse https as int: strconv.ParseInt: parsing "https": invalid syntax`
+	if err == nil || err.Error() != want {
+		t.Errorf("BindEnvFromMap() error = %v, want %s", err, want)
+	}
+}
+
+func TestBindEnvSizedIntSliceOverflow(t *testing.T) {
+	type Config struct {
+		Ports  []int16  `env:"PORTS"`
+		Masks  []uint8  `env:"MASKS"`
+		Limits []*int32 `env:"LIMITS"`
+	}
+
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr string
+	}{
+		{
+			name:    "Overflow",
+			envVars: map[string]string{"PORTS": "70000"},
+			wantErr: "unable to set value for field Ports[0]. failed to parse 70000 as int16: element 70000 overflows int16 at index 0: value out of range",
+		},
+		{
+			name:    "Underflow",
+			envVars: map[string]string{"PORTS": "80,-40000"},
+			wantErr: "unable to set value for field Ports[1]. failed to parse -40000 as int16: element -40000 overflows int16 at index 1: value out of range",
+		},
+		{
+			name:    "Unsigned",
+			envVars: map[string]string{"MASKS": "255,256"},
+			wantErr: "unable to set value for field Masks[1]. failed to parse 256 as uint8: element 256 overflows uint8 at index 1: value out of range",
+		},
+		{
+			name:    "Pointer elements",
+			envVars: map[string]string{"LIMITS": "3000000000"},
+			wantErr: "unable to set value for field Limits[0]. failed to parse 3000000000 as int32: element 3000000000 overflows int32 at index 0: value out of range",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, tt.envVars)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+			}
+			if !errors.Is(err, strconv.ErrRange) {
+				t.Errorf("BindEnvFromMap() error = %v, want it to wrap strconv.ErrRange", err)
+			}
+		})
+	}
+}
+
+func TestBindEnvBoolSlice(t *testing.T) {
+	type Config struct {
+		Flags   []bool `env:"FLAGS"`
+		Toggles []bool `env:"TOGGLES,skip-empty"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected Config
+		wantErr  string
+	}{
+		{
+			name:     "Trims elements",
+			envVars:  map[string]string{"FLAGS": "true, 0 ,1,FALSE"},
+			expected: Config{Flags: []bool{true, false, true, false}},
+		},
+		{
+			name:     "Skips empty elements",
+			envVars:  map[string]string{"TOGGLES": "1,,0, ,"},
+			expected: Config{Toggles: []bool{true, false}},
+		},
+		{
+			name:    "Empty element without skip-empty",
+			envVars: map[string]string{"FLAGS": "1,,0"},
+			wantErr: `unable to set value for field Flags[1]. failed to parse  as bool: strconv.ParseBool: parsing "": invalid syntax`,
+		},
+		{
+			name:    "Invalid element",
+			envVars: map[string]string{"TOGGLES": "1,maybe"},
+			wantErr: `unable to set value for field Toggles[1]. failed to parse maybe as bool: strconv.ParseBool: parsing "maybe": invalid syntax`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, tt.envVars)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvFromMap() error = %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("BindEnvFromMap() got = %v, want %v", config, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvExtendedBools(t *testing.T) {
+	type Config struct {
+		Flag  bool   `env:"FLAG" env-bool-mode:"extended"`
+		Flags []bool `env:"FLAGS,bool-mode=extended"`
+	}
+
+	tests := []struct {
+		value   string
+		want    bool
+		wantErr bool
+	}{
+		// Kubernetes downward API and YAML style values
+		{value: "True", want: true},
+		{value: "False", want: false},
+		{value: "TRUE", want: true},
+		{value: "yes", want: true},
+		{value: "No", want: false},
+		{value: "y", want: true},
+		{value: "n", want: false},
+		{value: "on", want: true},
+		{value: "OFF", want: false},
+		// feature flag tooling
+		{value: "enabled", want: true},
+		{value: "Disabled", want: false},
+		{value: "enable", want: true},
+		{value: "disable", want: false},
+		// numeric and short forms
+		{value: "1", want: true},
+		{value: "0", want: false},
+		{value: "t", want: true},
+		{value: "F", want: false},
+		{value: " yes ", want: true},
+		{value: "2", wantErr: true},
+		{value: "maybe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, map[string]string{"FLAG": tt.value, "FLAGS": "on," + tt.value})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("BindEnvFromMap() expected error for %q, got nil", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvFromMap() error = %v", err)
+			}
+			expected := Config{Flag: tt.want, Flags: []bool{true, tt.want}}
+			if !reflect.DeepEqual(config, expected) {
+				t.Errorf("BindEnvFromMap() got = %v, want %v", config, expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvNumericBools(t *testing.T) {
+	type Config struct {
+		Flag  bool   `env:"FLAG" env-bool-mode:"numeric"`
+		Flags []bool `env:"FLAGS,bool-mode=numeric"`
+	}
+
+	tests := []struct {
+		value   string
+		want    bool
+		wantErr string
+	}{
+		{value: "1", want: true},
+		{value: "0", want: false},
+		{value: "2", want: true},
+		{value: "-1", want: true},
+		{value: " 10 ", want: true},
+		{value: "000", want: false},
+		{value: "99999999999999999999", want: true},
+		{value: "true", wantErr: `unable to set value for field Flag. failed to parse true as bool: strconv.ParseInt: parsing "true": invalid syntax`},
+		{value: "1.5", wantErr: `unable to set value for field Flag. failed to parse 1.5 as bool: strconv.ParseInt: parsing "1.5": invalid syntax`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, map[string]string{"FLAG": tt.value, "FLAGS": "0," + tt.value})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvFromMap() error = %v", err)
+			}
+			expected := Config{Flag: tt.want, Flags: []bool{false, tt.want}}
+			if !reflect.DeepEqual(config, expected) {
+				t.Errorf("BindEnvFromMap() got = %v, want %v", config, expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvStructMap(t *testing.T) {
+	type Worker struct {
+		Host        string `env:"HOST" env-required:"true"`
+		Concurrency int    `env:"CONCURRENCY" env-default:"4"`
+	}
+	type Config struct {
+		Workers map[string]Worker `env:"WORKER"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected Config
+		wantErr  string
+	}{
+		{
+			name: "Discovers workers and applies defaults",
+			envVars: map[string]string{
+				"WORKER_ingest_HOST":        "10.0.0.1",
+				"WORKER_ingest_CONCURRENCY": "16",
+				"WORKER_batch_jobs_HOST":    "10.0.0.2",
+			},
+			expected: Config{Workers: map[string]Worker{
+				"ingest":     {Host: "10.0.0.1", Concurrency: 16},
+				"batch_jobs": {Host: "10.0.0.2", Concurrency: 4},
+			}},
+		},
+		{
+			name:     "No workers",
+			envVars:  map[string]string{},
+			expected: Config{},
+		},
+		{
+			name:    "Missing required field",
+			envVars: map[string]string{"WORKER_ingest_CONCURRENCY": "16"},
+			wantErr: "missing required environment variables: WORKER_ingest_HOST",
+		},
+		{
+			name:    "Invalid field",
+			envVars: map[string]string{"WORKER_ingest_HOST": "10.0.0.1", "WORKER_ingest_CONCURRENCY": "many"},
+			wantErr: "unable to set value for field Workers: failed to bind ingest: unable to set value for field Concurrency. failed to parse many as int: strconv.ParseInt: parsing \"many\": invalid syntax",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, tt.envVars)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvFromMap() error = %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("BindEnvFromMap() got = %v, want %v", config, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvAllowEmpty(t *testing.T) {
+	type Config struct {
+		Fallback string `env:"TEST_ALLOW_EMPTY_FALLBACK" env-default:"default"`
+		Cleared  string `env:"TEST_ALLOW_EMPTY_CLEARED" env-default:"default" env-allow-empty:"true"`
+		Count    int    `env:"TEST_ALLOW_EMPTY_COUNT" env-default:"5" env-allow-empty:"true"`
+		Unset    string `env:"TEST_ALLOW_EMPTY_UNSET" env-default:"default" env-allow-empty:"true"`
+	}
+
+	envVars := map[string]string{
+		"TEST_ALLOW_EMPTY_FALLBACK": "",
+		"TEST_ALLOW_EMPTY_CLEARED":  "",
+		"TEST_ALLOW_EMPTY_COUNT":    "",
+	}
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	config := Config{Count: 10}
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+
+	expected := Config{Fallback: "default", Cleared: "", Count: 0, Unset: "default"}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnv() got = %v, want %v", config, expected)
+	}
+}
+
+func TestResetDefaults(t *testing.T) {
+	type Nested struct {
+		Level string `env:"TEST_RESET_LEVEL" env-default:"info"`
+	}
+	type Config struct {
+		Port     int      `env:"TEST_RESET_PORT" env-default:"8080"`
+		Host     string   `env:"TEST_RESET_HOST"`
+		Tags     []string `env:"TEST_RESET_TAGS" env-default:"a,b"`
+		Untagged string
+		Nested   Nested
+	}
+
+	config := Config{
+		Port:     9090,
+		Host:     "example.com",
+		Tags:     []string{"c"},
+		Untagged: "kept",
+		Nested:   Nested{Level: "debug"},
+	}
+	if err := ResetDefaults(&config); err != nil {
+		t.Fatalf("ResetDefaults() error = %v", err)
+	}
+
+	expected := Config{
+		Port:     8080,
+		Tags:     []string{"a", "b"},
+		Untagged: "kept",
+		Nested:   Nested{Level: "info"},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("ResetDefaults() got = %v, want %v", config, expected)
+	}
+}
+
+func TestBindEnvFromMap(t *testing.T) {
+	type Upstream struct {
+		URL string `env:"URL"`
+	}
+	type Config struct {
+		Host      string     `env:"TEST_MAP_HOST"`
+		Port      int        `env:"TEST_MAP_PORT" env-default:"8080"`
+		Tags      []string   `env:"TEST_MAP_TAGS"`
+		Upstreams []Upstream `env:"TEST_MAP_UPSTREAM"`
+	}
+
+	os.Setenv("TEST_MAP_HOST", "from-environment")
+	defer os.Unsetenv("TEST_MAP_HOST")
+
+	var config Config
+	err := BindEnvFromMap(&config, map[string]string{
+		"TEST_MAP_HOST":           "from-map",
+		"TEST_MAP_TAGS":           "a,b",
+		"TEST_MAP_UPSTREAM_0_URL": "http://a",
+	})
+	if err != nil {
+		t.Fatalf("BindEnvFromMap() error = %v", err)
+	}
+
+	expected := Config{
+		Host:      "from-map",
+		Port:      8080,
+		Tags:      []string{"a", "b"},
+		Upstreams: []Upstream{{URL: "http://a"}},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvFromMap() got = %v, want %v", config, expected)
+	}
+}
+
+func TestBindEnvRequiredSlice(t *testing.T) {
+	type Config struct {
+		Origins []string `env:"ORIGINS,required,allow-empty,skip-empty"`
+	}
+
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr string
+	}{
+		{
+			name:    "Elements",
+			envVars: map[string]string{"ORIGINS": "a,b"},
+		},
+		{
+			name:    "Unset",
+			envVars: map[string]string{},
+			wantErr: "missing required environment variables: ORIGINS",
+		},
+		{
+			name:    "Empty value",
+			envVars: map[string]string{"ORIGINS": ""},
+			wantErr: "unable to set value for field Origins. required slice has no elements",
+		},
+		{
+			name:    "Only empty elements",
+			envVars: map[string]string{"ORIGINS": ",,"},
+			wantErr: "unable to set value for field Origins. required slice has no elements",
+		},
+		{
+			name:    "Empty JSON array",
+			envVars: map[string]string{"ORIGINS": "[]"},
+			wantErr: "unable to set value for field Origins. required slice has no elements",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, tt.envVars)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("BindEnvFromMap() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBindEnvInlineStruct(t *testing.T) {
+	type Config struct {
+		Server struct {
+			Host string `env:"HOST" env-default:"localhost"`
+			Port int    `env:"PORT"`
+			TLS  struct {
+				Enabled bool   `env:"ENABLED"`
+				Cert    string `env:"CERT" env-required-if:"Enabled"`
+			} `env-prefix:"TLS_"`
+		} `env-prefix:"SERVER_"`
+		Limits struct {
+			Max int `env:"MAX"`
+		}
+	}
+
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		check   func(c Config) bool
+		wantErr string
+	}{
+		{
+			name:    "Prefixes",
+			envVars: map[string]string{"SERVER_PORT": "8080", "SERVER_TLS_ENABLED": "true", "SERVER_TLS_CERT": "/cert", "MAX": "3"},
+			check: func(c Config) bool {
+				return c.Server.Host == "localhost" && c.Server.Port == 8080 && c.Server.TLS.Enabled && c.Server.TLS.Cert == "/cert" &&
+					c.Limits.Max == 3
+			},
+		},
+		{
+			name:    "Invalid value",
+			envVars: map[string]string{"SERVER_PORT": "http"},
+			wantErr: `unable to set value for field Server: unable to set value for field Port. failed to parse http as int: strconv.ParseInt: parsing "http": invalid syntax`,
+		},
+		{
+			name:    "Nested error",
+			envVars: map[string]string{"SERVER_TLS_ENABLED": "true"},
+			wantErr: "unable to set value for field Server: unable to set value for field TLS: unable to set value for field Cert. SERVER_TLS_CERT is required when Enabled is true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, tt.envVars)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvFromMap() error = %v", err)
+			}
+			if !tt.check(config) {
+				t.Errorf("BindEnvFromMap() got = %+v", config)
+			}
+		})
+	}
+
+	var config Config
+	config.Server.Port = 1
+	if err := ResetDefaults(&config); err != nil {
+		t.Fatalf("ResetDefaults() error = %v", err)
+	}
+	if config.Server.Host != "localhost" || config.Server.Port != 0 {
+		t.Errorf("ResetDefaults() got = %+v", config)
+	}
+
+	var paths []string
+	err := BindEnvWith(&config, withLookupMap(map[string]string{"SERVER_PORT": "80"}), WithFieldObserver(func(info FieldInfo) {
+		paths = append(paths, info.Path)
+	}))
+	if err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+	expected := []string{"Server.Host", "Server.Port", "Server.TLS.Enabled", "Server.TLS.Cert", "Limits.Max"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("BindEnvWith() observed paths = %v, want %v", paths, expected)
+	}
+}
+
+func TestBindEnvRequiredIf(t *testing.T) {
+	type TLS struct {
+		Enabled bool   `env:"TLS_ENABLED"`
+		Key     string `env:"TLS_KEY" env-required-if:"Enabled"`
+	}
+	type Config struct {
+		TLS      TLS
+		Metrics  *bool  `env:"METRICS"`
+		Endpoint string `env:"METRICS_ENDPOINT,required-if=Metrics"`
+	}
+
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr string
+	}{
+		{
+			name:    "Condition false",
+			envVars: map[string]string{"TLS_ENABLED": "false"},
+		},
+		{
+			name:    "Condition true with value",
+			envVars: map[string]string{"TLS_ENABLED": "true", "TLS_KEY": "/etc/tls.key"},
+		},
+		{
+			name:    "Condition true without value",
+			envVars: map[string]string{"TLS_ENABLED": "true"},
+			wantErr: "unable to set value for field TLS: unable to set value for field Key. TLS_KEY is required when Enabled is true",
+		},
+		{
+			name:    "Pointer condition true without value",
+			envVars: map[string]string{"METRICS": "true"},
+			wantErr: "unable to set value for field Endpoint. METRICS_ENDPOINT is required when Metrics is true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, tt.envVars)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("BindEnvFromMap() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+
+	var invalid struct {
+		Key string `env:"KEY" env-required-if:"Missing"`
+	}
+	if err := BindEnvFromMap(&invalid, map[string]string{}); err == nil {
+		t.Errorf("BindEnvFromMap() expected error for an unknown condition field, got nil")
+	}
+}
+
+func TestBindEnvFromEnviron(t *testing.T) {
+	type Config struct {
+		Host  string `env:"TEST_ENVIRON_HOST"`
+		Port  int    `env:"TEST_ENVIRON_PORT" env-default:"8080"`
+		Query string `env:"TEST_ENVIRON_QUERY"`
+	}
+
+	os.Setenv("TEST_ENVIRON_HOST", "from-environment")
+	defer os.Unsetenv("TEST_ENVIRON_HOST")
+
+	var config Config
+	err := BindEnvFromEnviron(&config, []string{
+		"TEST_ENVIRON_HOST=first",
+		"TEST_ENVIRON_QUERY=a=1&b=2",
+		"INVALID",
+		"TEST_ENVIRON_HOST=from-environ",
+	})
+	if err != nil {
+		t.Fatalf("BindEnvFromEnviron() error = %v", err)
+	}
+
+	expected := Config{Host: "from-environ", Port: 8080, Query: "a=1&b=2"}
+	if config != expected {
+		t.Errorf("BindEnvFromEnviron() got = %v, want %v", config, expected)
+	}
+}
+
+func TestBindEnvRequired(t *testing.T) {
+	type Worker struct {
+		Name string `env:"NAME" env-required:"true"`
+	}
+	type Nested struct {
+		Token string `env:"TEST_REQUIRED_TOKEN" env-required:"true"`
+	}
+	type Config struct {
+		Host    string `env:"TEST_REQUIRED_HOST" env-required:"true"`
+		Port    int    `env:"TEST_REQUIRED_PORT" env-required:"true" env-default:"8080"`
+		Debug   bool   `env:"TEST_REQUIRED_DEBUG"`
+		Nested  Nested
+		Workers []Worker `env:"TEST_REQUIRED_WORKER"`
+	}
+
+	os.Setenv("TEST_REQUIRED_DEBUG", "true")
+	os.Setenv("TEST_REQUIRED_WORKER_0_OTHER", "x")
+	defer os.Unsetenv("TEST_REQUIRED_DEBUG")
+	defer os.Unsetenv("TEST_REQUIRED_WORKER_0_OTHER")
+
+	var config Config
+	err := BindEnv(&config)
+	if err == nil {
+		t.Fatalf("BindEnv() expected error, got nil")
+	}
+
+	expected := "missing required environment variables: TEST_REQUIRED_HOST, TEST_REQUIRED_TOKEN, TEST_REQUIRED_WORKER_0_NAME"
+	if err.Error() != expected {
+		t.Errorf("BindEnv() error = %v, want %v", err, expected)
+	}
+	if !reflect.DeepEqual(config, Config{}) {
+		t.Errorf("BindEnv() unexpectedly modified config: %v", config)
+	}
+
+	os.Setenv("TEST_REQUIRED_HOST", "localhost")
+	os.Setenv("TEST_REQUIRED_TOKEN", "token")
+	os.Setenv("TEST_REQUIRED_WORKER_0_NAME", "worker")
+	defer os.Unsetenv("TEST_REQUIRED_HOST")
+	defer os.Unsetenv("TEST_REQUIRED_TOKEN")
+	defer os.Unsetenv("TEST_REQUIRED_WORKER_0_NAME")
+
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+	if config.Host != "localhost" || config.Port != 8080 || config.Nested.Token != "token" {
+		t.Errorf("BindEnv() got = %v", config)
+	}
+}
+
+func TestBindEnvPointer(t *testing.T) {
+	type Config struct {
+		Flag  *bool   `env:"TEST_POINTER_FLAG"`
+		Count *int    `env:"TEST_POINTER_COUNT"`
+		Name  *string `env:"TEST_POINTER_NAME" env-default:"default"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		wantFlag *bool
+	}{
+		{
+			name:     "Unset",
+			envVars:  map[string]string{},
+			wantFlag: nil,
+		},
+		{
+			name:     "Explicitly false",
+			envVars:  map[string]string{"TEST_POINTER_FLAG": "false"},
+			wantFlag: func() *bool { b := false; return &b }(),
+		},
+		{
+			name:     "Explicitly true",
+			envVars:  map[string]string{"TEST_POINTER_FLAG": "true"},
+			wantFlag: func() *bool { b := true; return &b }(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.envVars {
+				os.Setenv(k, v)
+			}
+			defer func() {
+				for k := range tt.envVars {
+					os.Unsetenv(k)
+				}
+			}()
+
+			var config Config
+			if err := BindEnv(&config); err != nil {
+				t.Fatalf("BindEnv() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(config.Flag, tt.wantFlag) {
+				t.Errorf("Flag got = %v, want %v", config.Flag, tt.wantFlag)
+			}
+			if config.Count != nil {
+				t.Errorf("Count got = %v, want nil", *config.Count)
+			}
+			if config.Name == nil || *config.Name != "default" {
+				t.Errorf("Name got = %v, want pointer to default", config.Name)
+			}
+		})
+	}
+}
+
+func TestBindEnvSliceElementTypes(t *testing.T) {
+	type Level string
+	type Config struct {
+		Times   []time.Time `env:"TEST_SLICE_TIMES" env-layout:"2006-01-02"`
+		Sizes   []int       `env:"TEST_SLICE_SIZES" env-unit:"bytes"`
+		Levels  []Level     `env:"TEST_SLICE_LEVELS"`
+		Weights []*float64  `env:"TEST_SLICE_WEIGHTS"`
+	}
+
+	envVars := map[string]string{
+		"TEST_SLICE_TIMES":   "2023-01-02,2024-03-04",
+		"TEST_SLICE_SIZES":   "1KB,2KiB",
+		"TEST_SLICE_LEVELS":  "debug,info",
+		"TEST_SLICE_WEIGHTS": "0.5,1.5",
+	}
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	var config Config
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+
+	half, oneAndHalf := 0.5, 1.5
+	expected := Config{
+		Times: []time.Time{
+			time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+		},
+		Sizes:   []int{1000, 2048},
+		Levels:  []Level{"debug", "info"},
+		Weights: []*float64{&half, &oneAndHalf},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnv() got = %v, want %v", config, expected)
+	}
+
+	os.Setenv("TEST_SLICE_TIMES", "2023-01-02,yesterday")
+	var parseErr *ParseError
+	if err := BindEnv(&config); !errors.As(err, &parseErr) || parseErr.Value != "yesterday" {
+		t.Errorf("BindEnv() error = %v, want ParseError for yesterday", err)
+	}
+}
+
+func TestBindEnvSliceSeparator(t *testing.T) {
+	type Config struct {
+		Semicolon []int    `env:"TEST_SEPARATOR_SEMICOLON" env-separator:";"`
+		Newline   []string `env:"TEST_SEPARATOR_NEWLINE" env-separator:"\\n"`
+		Tab       []string `env:"TEST_SEPARATOR_TAB" env-separator:"\t"`
+		Quoted    []string `env:"TEST_SEPARATOR_QUOTED" env-separator:";" env-quoted:"true"`
+	}
+
+	envVars := map[string]string{
+		"TEST_SEPARATOR_SEMICOLON": "1;2;3",
+		"TEST_SEPARATOR_NEWLINE":   "first line\nsecond line",
+		"TEST_SEPARATOR_TAB":       "a\tb",
+		"TEST_SEPARATOR_QUOTED":    `"a;b";c`,
+	}
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	var config Config
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+
+	expected := Config{
+		Semicolon: []int{1, 2, 3},
+		Newline:   []string{"first line", "second line"},
+		Tab:       []string{"a", "b"},
+		Quoted:    []string{"a;b", "c"},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnv() got = %q, want %q", config, expected)
+	}
+}
+
+func TestBindEnvJSONSlice(t *testing.T) {
+	type Config struct {
+		Hosts    []string `env:"TEST_JSON_HOSTS"`
+		Ports    []int    `env:"TEST_JSON_PORTS" env-format:"json"`
+		Brackets []string `env:"TEST_JSON_BRACKETS"`
+	}
+
+	envVars := map[string]string{
+		"TEST_JSON_HOSTS":    `["a,1","b"]`,
+		"TEST_JSON_PORTS":    `[80, 443]`,
+		"TEST_JSON_BRACKETS": `[a],[b]`,
+	}
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	var config Config
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+
+	expected := Config{
+		Hosts:    []string{"a,1", "b"},
+		Ports:    []int{80, 443},
+		Brackets: []string{"[a]", "[b]"},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnv() got = %v, want %v", config, expected)
+	}
+
+	os.Setenv("TEST_JSON_PORTS", "80,443")
+	if err := BindEnv(&config); err == nil {
+		t.Errorf("BindEnv() expected error for non-JSON value with env-format json, got nil")
+	}
+}
+
+func TestBindEnvDefaultFrom(t *testing.T) {
+	type Config struct {
+		MetricsHost string `env:"TEST_DEFAULT_FROM_METRICS_HOST" env-default-from:"TEST_DEFAULT_FROM_HOST" env-default:"localhost"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			name: "Own variable",
+			envVars: map[string]string{
+				"TEST_DEFAULT_FROM_METRICS_HOST": "metrics",
+				"TEST_DEFAULT_FROM_HOST":         "host",
+			},
+			expected: "metrics",
+		},
+		{
+			name:     "Default from variable",
+			envVars:  map[string]string{"TEST_DEFAULT_FROM_HOST": "host"},
+			expected: "host",
+		},
+		{
+			name:     "Static default",
+			envVars:  map[string]string{},
+			expected: "localhost",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.envVars {
+				os.Setenv(k, v)
+			}
+			defer func() {
+				for k := range tt.envVars {
+					os.Unsetenv(k)
+				}
+			}()
+
+			var config Config
+			if err := BindEnv(&config); err != nil {
+				t.Fatalf("BindEnv() error = %v", err)
+			}
+			if config.MetricsHost != tt.expected {
+				t.Errorf("BindEnv() got = %v, want %v", config.MetricsHost, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvMap(t *testing.T) {
+	type Config struct {
+		Labels map[string]string   `env:"TEST_MAP_LABELS"`
+		Limits map[string]int      `env:"TEST_MAP_LIMITS" env-separator:";"`
+		Routes map[string][]string `env:"TEST_MAP_ROUTES"`
+		Groups map[string][]int    `env:"TEST_MAP_GROUPS" env-value-separator:" "`
+	}
+
+	envVars := map[string]string{
+		"TEST_MAP_LABELS": "env=prod,team=core",
+		"TEST_MAP_LIMITS": "cpu=2;memory=512",
+		"TEST_MAP_ROUTES": "api=a|b,web=c",
+		"TEST_MAP_GROUPS": "odd=1 3,even=2 4",
+	}
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	var config Config
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+
+	expected := Config{
+		Labels: map[string]string{"env": "prod", "team": "core"},
+		Limits: map[string]int{"cpu": 2, "memory": 512},
+		Routes: map[string][]string{"api": {"a", "b"}, "web": {"c"}},
+		Groups: map[string][]int{"odd": {1, 3}, "even": {2, 4}},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnv() got = %v, want %v", config, expected)
+	}
+
+	os.Setenv("TEST_MAP_ROUTES", "api=a|b,web")
+	if err := BindEnv(&config); err == nil {
+		t.Errorf("BindEnv() expected error for malformed pair, got nil")
+	}
+}
+
+func TestBindEnvJSONMap(t *testing.T) {
+	type Config struct {
+		Settings map[string]interface{} `env:"SETTINGS" env-format:"json"`
+		Limits   map[string]int         `env:"LIMITS,format=json"`
+	}
+
+	var config Config
+	err := BindEnvFromMap(&config, map[string]string{
+		"SETTINGS": `{"name": "plugin", "retries": 3, "tags": ["a", "b"], "tls": {"enabled": true}}`,
+		"LIMITS":   `{"cpu": 2, "memory": 512}`,
+	})
+	if err != nil {
+		t.Fatalf("BindEnvFromMap() error = %v", err)
+	}
+
+	expected := Config{
+		Settings: map[string]interface{}{
+			"name":    "plugin",
+			"retries": float64(3),
+			"tags":    []interface{}{"a", "b"},
+			"tls":     map[string]interface{}{"enabled": true},
+		},
+		Limits: map[string]int{"cpu": 2, "memory": 512},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvFromMap() got = %v, want %v", config, expected)
+	}
+
+	err = BindEnvFromMap(&config, map[string]string{"SETTINGS": `{"name": `})
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Name != "Settings" || parseErr.Kind != "json" {
+		t.Errorf("BindEnvFromMap() error = %v, want a json ParseError for Settings", err)
+	}
+}
+
+func TestBindEnvLength(t *testing.T) {
+	type Config struct {
+		Origins []string          `env:"TEST_LEN_ORIGINS" env-min-len:"1" env-max-len:"3"`
+		Labels  map[string]string `env:"TEST_LEN_LABELS" env-max-len:"1"`
+	}
+
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "Within bounds",
+			envVars: map[string]string{"TEST_LEN_ORIGINS": "a,b,c", "TEST_LEN_LABELS": "env=prod"},
+		},
+		{
+			name:    "Too many slice elements",
+			envVars: map[string]string{"TEST_LEN_ORIGINS": "a,b,c,d"},
+			wantErr: true,
+		},
+		{
+			name:    "Too few slice elements",
+			envVars: map[string]string{"TEST_LEN_ORIGINS": "[]"},
+			wantErr: true,
+		},
+		{
+			name:    "Unset slice with a minimum length",
+			envVars: map[string]string{"TEST_LEN_LABELS": "env=prod"},
+			wantErr: true,
+		},
+		{
+			name:    "Too many map elements",
+			envVars: map[string]string{"TEST_LEN_LABELS": "env=prod,team=core"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.envVars {
+				os.Setenv(k, v)
+			}
+			defer func() {
+				for k := range tt.envVars {
+					os.Unsetenv(k)
+				}
+			}()
+
+			var config Config
+			err := BindEnv(&config)
+			if tt.wantErr && err == nil {
+				t.Errorf("BindEnv() expected error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("BindEnv() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestBindEnvStringLength(t *testing.T) {
+	type Config struct {
+		APIKey string `env:"API_KEY" env-secret:"true" env-min-len:"32"`
+		Region string `env:"REGION" env-min-len:"2" env-max-len:"4"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected Config
+		wantErr  string
+	}{
+		{
+			name:     "Within bounds",
+			envVars:  map[string]string{"API_KEY": strings.Repeat("k", 32), "REGION": "eu"},
+			expected: Config{APIKey: strings.Repeat("k", 32), Region: "eu"},
+		},
+		{
+			name:     "Counts characters",
+			envVars:  map[string]string{"API_KEY": strings.Repeat("k", 32), "REGION": "日本"},
+			expected: Config{APIKey: strings.Repeat("k", 32), Region: "日本"},
+		},
+		{
+			name:    "Short secret is redacted",
+			envVars: map[string]string{"API_KEY": "hunter2", "REGION": "eu"},
+			wantErr: "unable to set value for field APIKey. **** has 7 characters, expected at least 32",
+		},
+		{
+			name:    "Too short",
+			envVars: map[string]string{"API_KEY": strings.Repeat("k", 32), "REGION": "e"},
+			wantErr: "unable to set value for field Region. e has 1 characters, expected at least 2",
+		},
+		{
+			name:    "Too long",
+			envVars: map[string]string{"API_KEY": strings.Repeat("k", 32), "REGION": "europe"},
+			wantErr: "unable to set value for field Region. europe has 6 characters, expected at most 4",
+		},
+		{
+			name:    "Unset",
+			envVars: map[string]string{"REGION": "eu"},
+			wantErr: "missing required environment variables: API_KEY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, tt.envVars)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+				}
+				if err != nil && strings.Contains(err.Error(), "hunter2") {
+					t.Errorf("BindEnvFromMap() error leaks the secret value: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvFromMap() error = %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("BindEnvFromMap() got = %+v, want %+v", config, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvRange(t *testing.T) {
+	type Config struct {
+		Timeout time.Duration `env:"TEST_RANGE_TIMEOUT" env-min:"0s" env-max:"1m"`
+		Workers int           `env:"TEST_RANGE_WORKERS" env-min:"1" env-max:"64"`
+		Ratio   float64       `env:"TEST_RANGE_RATIO,min=0,max=1"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		clamp    bool
+		expected Config
+		wantErr  string
+	}{
+		{
+			name:     "Within bounds",
+			envVars:  map[string]string{"TEST_RANGE_TIMEOUT": "30s", "TEST_RANGE_WORKERS": "8", "TEST_RANGE_RATIO": "0.5"},
+			expected: Config{Timeout: 30 * time.Second, Workers: 8, Ratio: 0.5},
+		},
+		{
+			name:    "Negative duration",
+			envVars: map[string]string{"TEST_RANGE_TIMEOUT": "-5s"},
+			wantErr: "unable to set value for field Timeout. -5s is less than the minimum 0s",
+		},
+		{
+			name:    "Duration too large",
+			envVars: map[string]string{"TEST_RANGE_TIMEOUT": "2h"},
+			wantErr: "unable to set value for field Timeout. 2h0m0s is greater than the maximum 1m0s",
+		},
+		{
+			name:    "Integer too small",
+			envVars: map[string]string{"TEST_RANGE_WORKERS": "0"},
+			wantErr: "unable to set value for field Workers. 0 is less than the minimum 1",
+		},
+		{
+			name:    "Float too large",
+			envVars: map[string]string{"TEST_RANGE_RATIO": "1.5"},
+			wantErr: "unable to set value for field Ratio. 1.5 is greater than the maximum 1",
+		},
+		{
+			name:     "Clamped",
+			envVars:  map[string]string{"TEST_RANGE_TIMEOUT": "-5s", "TEST_RANGE_WORKERS": "100"},
+			clamp:    true,
+			expected: Config{Timeout: 0, Workers: 64},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvWith(&config, withLookupMap(tt.envVars), WithClamp(tt.clamp))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvWith() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvWith() error = %v", err)
+			}
+			if config != tt.expected {
+				t.Errorf("BindEnvWith() got = %v, want %v", config, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvFallbackNames(t *testing.T) {
+	type Config struct {
+		DatabaseURL string `env:"TEST_FALLBACK_DATABASE_URL,TEST_FALLBACK_DB_URL" env-default:"postgres://localhost"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected string
+	}{
+		{
+			name: "First name wins",
+			envVars: map[string]string{
+				"TEST_FALLBACK_DATABASE_URL": "postgres://new",
+				"TEST_FALLBACK_DB_URL":       "postgres://old",
+			},
+			expected: "postgres://new",
+		},
+		{
+			name:     "Fallback name",
+			envVars:  map[string]string{"TEST_FALLBACK_DB_URL": "postgres://old"},
+			expected: "postgres://old",
+		},
+		{
+			name: "Empty first name falls through",
+			envVars: map[string]string{
+				"TEST_FALLBACK_DATABASE_URL": "",
+				"TEST_FALLBACK_DB_URL":       "postgres://old",
+			},
+			expected: "postgres://old",
+		},
+		{
+			name:     "Default when all absent",
+			envVars:  map[string]string{},
+			expected: "postgres://localhost",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.envVars {
+				os.Setenv(k, v)
+			}
+			defer func() {
+				for k := range tt.envVars {
+					os.Unsetenv(k)
+				}
+			}()
+
+			var config Config
+			if err := BindEnv(&config); err != nil {
+				t.Fatalf("BindEnv() error = %v", err)
+			}
+			if config.DatabaseURL != tt.expected {
+				t.Errorf("BindEnv() got = %v, want %v", config.DatabaseURL, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvIntegers(t *testing.T) {
+	type Config struct {
+		Int8     int8          `env:"TEST_INTEGER_INT8"`
+		Uint16   uint16        `env:"TEST_INTEGER_UINT16"`
+		Int64    int64         `env:"TEST_INTEGER_INT64"`
+		Mask     uint32        `env:"TEST_INTEGER_MASK" env-base:"0"`
+		Umask    int           `env:"TEST_INTEGER_UMASK" env-base:"0"`
+		Hex      uint8         `env:"TEST_INTEGER_HEX" env-base:"16"`
+		Timeout  time.Duration `env:"TEST_INTEGER_TIMEOUT"`
+		Decimals int           `env:"TEST_INTEGER_DECIMAL"`
+	}
+
+	envVars := map[string]string{
+		"TEST_INTEGER_INT8":    "-8",
+		"TEST_INTEGER_UINT16":  "65535",
+		"TEST_INTEGER_INT64":   "9000000000",
+		"TEST_INTEGER_MASK":    "0xFF",
+		"TEST_INTEGER_UMASK":   "0o022",
+		"TEST_INTEGER_HEX":     "ff",
+		"TEST_INTEGER_TIMEOUT": "1m30s",
+		"TEST_INTEGER_DECIMAL": "010",
+	}
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	var config Config
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+
+	expected := Config{
+		Int8:     -8,
+		Uint16:   65535,
+		Int64:    9000000000,
+		Mask:     0xFF,
+		Umask:    0o022,
+		Hex:      0xff,
+		Timeout:  90 * time.Second,
+		Decimals: 10,
+	}
+	if config != expected {
+		t.Errorf("BindEnv() got = %v, want %v", config, expected)
+	}
+
+	invalid := map[string]string{
+		"TEST_INTEGER_INT8":   "128",
+		"TEST_INTEGER_UINT16": "-1",
+		"TEST_INTEGER_MASK":   "0xZZ",
+		"TEST_INTEGER_UMASK":  "0o9",
+	}
+	for k, v := range invalid {
+		t.Run(k, func(t *testing.T) {
+			os.Setenv(k, v)
+			defer os.Setenv(k, envVars[k])
+
+			if err := BindEnv(&config); err == nil {
+				t.Errorf("BindEnv() expected error for %s=%s, got nil", k, v)
+			}
+		})
+	}
+}
+
+func TestBindEnvInterface(t *testing.T) {
+	type Config struct {
+		Extra  interface{} `env:"TEST_INTERFACE_EXTRA" env-format:"json"`
+		Number any         `env:"TEST_INTERFACE_NUMBER"`
+		Plain  any         `env:"TEST_INTERFACE_PLAIN"`
+	}
+
+	envVars := map[string]string{
+		"TEST_INTERFACE_EXTRA":  `{"retries": 3, "hosts": ["a", "b"], "debug": true}`,
+		"TEST_INTERFACE_NUMBER": "42",
+		"TEST_INTERFACE_PLAIN":  "hello world",
+	}
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	var config Config
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+
+	expected := Config{
+		Extra: map[string]interface{}{
+			"retries": float64(3),
+			"hosts":   []interface{}{"a", "b"},
+			"debug":   true,
+		},
+		Number: float64(42),
+		Plain:  "hello world",
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnv() got = %v, want %v", config, expected)
+	}
+
+	os.Setenv("TEST_INTERFACE_EXTRA", `{"retries": `)
+	if err := BindEnv(&config); err == nil {
+		t.Errorf("BindEnv() expected error for invalid JSON, got nil")
+	}
+}
+
+func TestBindEnvStrict(t *testing.T) {
+	type Supported struct {
+		String   string            `env:"TEST_STRICT_STRING"`
+		Ints     []int             `env:"TEST_STRICT_INTS"`
+		Labels   map[string][]bool `env:"TEST_STRICT_LABELS"`
+		Pointer  *time.Duration    `env:"TEST_STRICT_POINTER"`
+		Value    Value[uint8]      `env:"TEST_STRICT_VALUE"`
+		Untagged chan int
+	}
+	type Unsupported struct {
+		Channel chan int `env:"TEST_STRICT_CHANNEL"`
+	}
+
+	if err := BindEnvStrict(&Supported{}); err != nil {
+		t.Errorf("BindEnvStrict() error = %v", err)
+	}
+
+	err := BindEnvStrict(&Unsupported{})
+	expected := "unable to set value for field Channel. unsupported type chan int of kind chan"
+	if err == nil || err.Error() != expected {
+		t.Errorf("BindEnvStrict() error = %v, want %v", err, expected)
+	}
+
+	if err := BindEnv(&Unsupported{}); err != nil {
+		t.Errorf("BindEnv() error = %v, want nil outside strict mode", err)
+	}
+}
+
+func TestMustBindEnv(t *testing.T) {
+	type Config struct {
+		Value string `env:"TEST_MUST_BIND"`
+	}
+
+	os.Setenv("TEST_MUST_BIND", "value")
+	defer os.Unsetenv("TEST_MUST_BIND")
+
+	var config Config
+	MustBindEnv(&config)
+	if config.Value != "value" {
+		t.Errorf("MustBindEnv() got = %v, want %v", config.Value, "value")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("MustBindEnv() expected panic for invalid input")
+		}
+	}()
+	MustBindEnv(config)
+}
+
 func TestBindEnvWithAutoRefresh(t *testing.T) {
 	type Config struct {
 		Value string `env:"TEST_AUTO_REFRESH"`
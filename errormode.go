@@ -0,0 +1,21 @@
+package ectoenv
+
+// ErrorMode controls how binding handles a field that fails to bind
+type ErrorMode int
+
+const (
+	// FailFast stops binding at the first error
+	FailFast ErrorMode = iota
+	// Collect binds every field it can and returns all errors joined
+	Collect
+)
+
+// fail returns err when binding stops at the first error. With Collect, it appends err to errs and returns nil so that
+// binding continues.
+func (b *binder) fail(errs *[]error, err error) error {
+	if b.errorMode == Collect {
+		*errs = append(*errs, err)
+		return nil
+	}
+	return err
+}
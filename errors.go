@@ -0,0 +1,53 @@
+package ectoenv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrRefreshStopped is wrapped by the error reported when a refresh loop stops after the number of consecutive failures
+// set with WithMaxRefreshErrors
+var ErrRefreshStopped = errors.New("refresh stopped")
+
+// ParseError is returned when the value of an environment variable cannot be converted to the type of the field it is
+// bound to. Callers can use errors.As to inspect the failing field and value.
+type ParseError struct {
+	// Name is the name of the struct field being set
+	Name string
+	// Value is the raw value that failed to parse
+	Value string
+	// Kind is the type the value was being parsed as, e.g. "int" or "bool"
+	Kind string
+	// Err is the underlying parse error
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("unable to set value for field %s. failed to parse %s as %s: %v", e.Name, e.Value, e.Kind, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// MissingRequiredError is returned when required variables, whether tagged with `env-required` or required by
+// WithRequireAll, have neither a value nor a default. It lists every missing variable rather than only the first, so
+// callers can use errors.As to tell the user exactly which variables to set.
+type MissingRequiredError struct {
+	// Keys are the names of the missing variables, in field order
+	Keys []string
+	// Fields are the paths of the fields the missing variables bind to, such as "Database.URL" or "Workers[0].Name",
+	// where Fields[i] is the field of Keys[i]
+	Fields []string
+}
+
+func (e *MissingRequiredError) Error() string {
+	return fmt.Sprintf("missing required environment variables: %s", strings.Join(e.Keys, ", "))
+}
+
+// add records the missing variable key of the field at path
+func (e *MissingRequiredError) add(key, path string) {
+	e.Keys = append(e.Keys, key)
+	e.Fields = append(e.Fields, path)
+}
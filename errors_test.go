@@ -0,0 +1,64 @@
+package ectoenv
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	type Config struct {
+		Port int `env:"TEST_PARSE_ERROR_PORT"`
+	}
+
+	os.Setenv("TEST_PARSE_ERROR_PORT", "eighty")
+	defer os.Unsetenv("TEST_PARSE_ERROR_PORT")
+
+	var config Config
+	err := BindEnv(&config)
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("BindEnv() error = %v, want *ParseError", err)
+	}
+	if parseErr.Name != "Port" || parseErr.Value != "eighty" || parseErr.Kind != "int" {
+		t.Errorf("ParseError got = %+v, want Name=Port Value=eighty Kind=int", parseErr)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("ParseError does not unwrap to strconv.ErrSyntax: %v", err)
+	}
+}
+
+func TestMissingRequiredError(t *testing.T) {
+	type Worker struct {
+		Name string `env:"NAME" env-required:"true"`
+	}
+	type Database struct {
+		URL string `env:"URL"`
+	}
+	type Config struct {
+		Host     string   `env:"HOST" env-required:"true"`
+		Port     int      `env:"PORT" env-default:"8080"`
+		Database Database `env-prefix:"DB_"`
+		Workers  []Worker `env:"WORKER"`
+	}
+
+	var config Config
+	err := BindEnvWith(&config, withLookupMap(map[string]string{"WORKER_0_OTHER": "x"}), WithRequireAll(true))
+
+	var missingErr *MissingRequiredError
+	if !errors.As(err, &missingErr) {
+		t.Fatalf("BindEnvWith() error = %v, want *MissingRequiredError", err)
+	}
+
+	expectedKeys := []string{"HOST", "DB_URL", "WORKER_0_NAME"}
+	if !reflect.DeepEqual(missingErr.Keys, expectedKeys) {
+		t.Errorf("MissingRequiredError.Keys got = %v, want %v", missingErr.Keys, expectedKeys)
+	}
+	expectedFields := []string{"Host", "Database.URL", "Workers[0].Name"}
+	if !reflect.DeepEqual(missingErr.Fields, expectedFields) {
+		t.Errorf("MissingRequiredError.Fields got = %v, want %v", missingErr.Fields, expectedFields)
+	}
+}
@@ -0,0 +1,26 @@
+package ectoenv
+
+import (
+	"flag"
+	"reflect"
+)
+
+var flagValueType = reflect.TypeOf((*flag.Value)(nil)).Elem()
+
+// isFlagValue reports whether a pointer to t implements flag.Value, so that a value of type t can be set with Set
+func isFlagValue(t reflect.Type) bool {
+	return t.Kind() != reflect.Ptr && reflect.PointerTo(t).Implements(flagValueType)
+}
+
+// setFlagValueField sets a field whose pointer implements flag.Value by calling Set, reusing the parsing of types that
+// are also used as command line flags. Set is called on a new zero value that is then stored in the field, so that
+// types which accumulate values across calls to Set, such as repeatable flags, hold only the current value after a
+// refresh, and so that the field does not need to be addressable, as the elements of a map are not.
+func setFlagValueField(field reflect.Value, name string, envValue string) error {
+	val := reflect.New(field.Type())
+	if err := val.Interface().(flag.Value).Set(envValue); err != nil {
+		return &ParseError{Name: name, Value: envValue, Kind: field.Type().String(), Err: err}
+	}
+	field.Set(val.Elem())
+	return nil
+}
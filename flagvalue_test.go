@@ -0,0 +1,80 @@
+package ectoenv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testLevel is a flag.Value with a value receiver for String and a pointer receiver for Set
+type testLevel int
+
+func (l testLevel) String() string {
+	return fmt.Sprint(int(l))
+}
+
+func (l *testLevel) Set(s string) error {
+	switch strings.ToLower(s) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	case "error":
+		*l = 2
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+// testList is a flag.Value that accumulates a value on every call to Set
+type testList []string
+
+func (l *testList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *testList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+func TestBindEnvFlagValue(t *testing.T) {
+	type Config struct {
+		Level    testLevel   `env:"LEVEL"`
+		Levels   []testLevel `env:"LEVELS"`
+		MaxLevel *testLevel  `env:"MAX_LEVEL"`
+		Hosts    testList    `env:"HOSTS"`
+	}
+
+	envVars := map[string]string{
+		"LEVEL":     "error",
+		"LEVELS":    "debug,info",
+		"MAX_LEVEL": "info",
+		"HOSTS":     "a,b",
+	}
+
+	var config Config
+	for i := 0; i < 2; i++ {
+		if err := BindEnvFromMap(&config, envVars); err != nil {
+			t.Fatalf("BindEnvFromMap() error = %v", err)
+		}
+	}
+
+	maxLevel := testLevel(1)
+	expected := Config{Level: 2, Levels: []testLevel{0, 1}, MaxLevel: &maxLevel, Hosts: testList{"a,b"}}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvFromMap() got = %v, want %v", config, expected)
+	}
+
+	err := BindEnvFromMap(&config, map[string]string{"LEVEL": "verbose"})
+	want := "unable to set value for field Level. failed to parse verbose as ectoenv.testLevel: unknown level"
+	if err == nil || err.Error() != want {
+		t.Errorf("BindEnvFromMap() error = %v, want %s", err, want)
+	}
+	if config.Level != 2 {
+		t.Errorf("BindEnvFromMap() changed Level to %v after a failed Set, want 2", config.Level)
+	}
+}
@@ -0,0 +1,51 @@
+package ectoenv
+
+import (
+	"reflect"
+	"strings"
+)
+
+// BindEnvGroup is like BindEnv but only binds the fields whose `env-group` tag includes group, which lets a command bind
+// just the configuration it needs. Fields outside the group are left unchanged and are not checked for required values.
+// Use BindEnvWith with WithGroup and WithUngrouped to also bind fields that have no group.
+// v: a non-nil pointer to a struct
+// group: the group of fields to bind
+// returns: an error if the provided value is not a non-nil pointer to a struct or if the value of an environment variable
+// cannot be converted to the type of its field
+func BindEnvGroup(v interface{}, group string) error {
+	return BindEnvWith(v, WithGroup(group))
+}
+
+// skipGroup reports whether the field is outside the group being bound. Ungrouped nested structs, including embedded
+// pointers to structs, are not skipped so that their fields can be considered individually.
+func (b *binder) skipGroup(field reflect.StructField) bool {
+	if b.group == "" || b.inGroup {
+		return false
+	}
+
+	if getTag(field, ENV_GROUP_TAG) == "" {
+		_, embedded := embeddedStructType(field)
+		return !b.includeUngrouped && !isNestedStruct(field.Type) && !embedded
+	}
+	return !b.hasGroup(field)
+}
+
+// enterGroup marks the fields of a nested struct as belonging to the group when the struct does, returning a function
+// that restores the previous state
+func (b *binder) enterGroup(field reflect.StructField) func() {
+	inGroup := b.inGroup
+	if b.group != "" && b.hasGroup(field) {
+		b.inGroup = true
+	}
+	return func() { b.inGroup = inGroup }
+}
+
+// hasGroup reports whether the field's `env-group` tag includes the group being bound
+func (b *binder) hasGroup(field reflect.StructField) bool {
+	for _, group := range strings.Split(getTag(field, ENV_GROUP_TAG), ",") {
+		if strings.TrimSpace(group) == b.group {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package ectoenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBindEnvGroup(t *testing.T) {
+	type Database struct {
+		URL string `env:"DATABASE_URL" env-required:"true"`
+	}
+	type Config struct {
+		LogLevel string   `env:"LOG_LEVEL" env-default:"info"`
+		Addr     string   `env:"ADDR" env-group:"serve" env-required:"true"`
+		Workers  int      `env:"WORKERS" env-group:"serve,worker" env-default:"4"`
+		Database Database `env-group:"migrate"`
+	}
+
+	envVars := map[string]string{
+		"ADDR":         ":8080",
+		"DATABASE_URL": "postgres://db",
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		envVars  map[string]string
+		expected Config
+		wantErr  bool
+	}{
+		{
+			name:     "Grouped fields",
+			opts:     []Option{WithGroup("serve")},
+			envVars:  envVars,
+			expected: Config{Addr: ":8080", Workers: 4},
+		},
+		{
+			name:     "Field in several groups",
+			opts:     []Option{WithGroup("worker")},
+			envVars:  envVars,
+			expected: Config{Workers: 4},
+		},
+		{
+			name:     "Nested struct group",
+			opts:     []Option{WithGroup("migrate")},
+			envVars:  envVars,
+			expected: Config{Database: Database{URL: "postgres://db"}},
+		},
+		{
+			name:     "Ungrouped fields",
+			opts:     []Option{WithGroup("migrate"), WithUngrouped(true)},
+			envVars:  envVars,
+			expected: Config{LogLevel: "info", Database: Database{URL: "postgres://db"}},
+		},
+		{
+			name:     "Required fields outside the group are not checked",
+			opts:     []Option{WithGroup("worker")},
+			envVars:  map[string]string{},
+			expected: Config{Workers: 4},
+		},
+		{
+			name:    "Required fields in the group are checked",
+			opts:    []Option{WithGroup("migrate")},
+			envVars: map[string]string{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvWith(&config, append(tt.opts, withLookupMap(tt.envVars))...)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("BindEnvWith() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvWith() error = %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("BindEnvWith() got = %v, want %v", config, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package ectoenv
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ENV_INDEXED_TAG is the tag used to bind a slice from one variable per element, such as TAGS_0, TAGS_1 and so on,
+// rather than from a single separated value
+var ENV_INDEXED_TAG = "env-indexed"
+
+// isIndexedSlice reports whether the field is a slice bound from indexed variables
+func isIndexedSlice(field reflect.StructField) bool {
+	return field.Type.Kind() == reflect.Slice && getTag(field, ENV_INDEXED_TAG) == "true"
+}
+
+// indexedKey returns the name of the variable of the element of an indexed slice at index
+func indexedKey(key string, index int) string {
+	return fmt.Sprintf("%s_%d", key, index)
+}
+
+// indexedValues returns the values of the variables KEY_0, KEY_1 and so on, stopping at the first index that is unset
+func (b *binder) indexedValues(key string) []string {
+	var values []string
+	for i := 0; ; i++ {
+		value, ok := b.lookup(indexedKey(key, i))
+		if !ok {
+			return values
+		}
+		values = append(values, value)
+	}
+}
+
+// setIndexedSliceField sets a slice from the values of its indexed variables, leaving the field unchanged if an element
+// cannot be parsed or the length is invalid
+func (b *binder) setIndexedSliceField(field reflect.Value, structField reflect.StructField, values []string) error {
+	opts := b.parseOptions(structField)
+	parsed := reflect.New(field.Type()).Elem()
+	if err := setSliceElements(parsed, structField, opts, values); err != nil {
+		return err
+	}
+	if err := b.validateElements(parsed, structField); err != nil {
+		return err
+	}
+	if err := validateLength(parsed, structField); err != nil {
+		return err
+	}
+	field.Set(parsed)
+	return nil
+}
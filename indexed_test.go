@@ -0,0 +1,84 @@
+package ectoenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBindEnvIndexedSlice(t *testing.T) {
+	type Config struct {
+		Tags    []string `env:"TAG" env-indexed:"true"`
+		Ports   []int    `env:"PORT,indexed" env-default:"80,443"`
+		Targets []string `env:"TARGET,indexed,required"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected Config
+		wantErr  string
+	}{
+		{
+			name: "Contiguous",
+			envVars: map[string]string{
+				"TAG_0": "a,b", "TAG_1": "c", "TAG_3": "skipped",
+				"PORT_0":   "8080",
+				"TARGET_0": "x",
+			},
+			expected: Config{Tags: []string{"a,b", "c"}, Ports: []int{8080}, Targets: []string{"x"}},
+		},
+		{
+			name:     "Default",
+			envVars:  map[string]string{"TAG_1": "not from zero", "TARGET_0": "x"},
+			expected: Config{Ports: []int{80, 443}, Targets: []string{"x"}},
+		},
+		{
+			name:     "Empty element",
+			envVars:  map[string]string{"TAG_0": "", "TARGET_0": "x"},
+			expected: Config{Tags: []string{""}, Ports: []int{80, 443}, Targets: []string{"x"}},
+		},
+		{
+			name:    "Invalid element",
+			envVars: map[string]string{"PORT_0": "80", "PORT_1": "http", "TARGET_0": "x"},
+			wantErr: `unable to set value for field Ports[1]. failed to parse http as int: strconv.ParseInt: parsing "http": invalid syntax`,
+		},
+		{
+			name:    "Missing required",
+			envVars: map[string]string{"TARGET_1": "x"},
+			wantErr: "missing required environment variables: TARGET",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, tt.envVars)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvFromMap() error = %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("BindEnvFromMap() got = %v, want %v", config, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMarshalEnvIndexedSlice(t *testing.T) {
+	type Config struct {
+		Tags []string `env:"TAG" env-indexed:"true"`
+	}
+
+	data, err := MarshalEnv(Config{Tags: []string{"a,b", "c"}})
+	if err != nil {
+		t.Fatalf("MarshalEnv() error = %v", err)
+	}
+	if expected := "TAG_0=a,b\nTAG_1=c\n"; string(data) != expected {
+		t.Errorf("MarshalEnv() got = %q, want %q", data, expected)
+	}
+}
@@ -0,0 +1,351 @@
+package ectoenv
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// REDACTED_VALUE is the value written in place of fields marked with the `env-secret` tag
+var REDACTED_VALUE = "****"
+
+// MarshalEnv serializes the provided struct into the dotenv format, writing one KEY=VALUE line for each field with an
+// `env` tag in field order. Fields marked with `env-secret:"true"` are written as REDACTED_VALUE.
+// v: a struct or a non-nil pointer to a struct
+// returns: the dotenv encoded struct or an error if the provided value is not a struct
+func MarshalEnv(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("provided value must be a struct or a non-nil pointer to a struct")
+	}
+
+	var buf bytes.Buffer
+	for _, entry := range newBinder().collectEnvEntries(rv, "", "", true) {
+		fmt.Fprintf(&buf, "%s=%s\n", entry.key, quoteEnvValue(entry.value))
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteEnvFile serializes the provided struct with MarshalEnv and writes it to the file at path, creating or truncating
+// it as needed.
+// path: the path of the file to write
+// v: a struct or a non-nil pointer to a struct
+// returns: an error if the struct cannot be serialized or the file cannot be written
+func WriteEnvFile(path string, v interface{}) error {
+	data, err := MarshalEnv(v)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// envEntry is a single serialized field of a struct
+type envEntry struct {
+	key   string
+	name  string
+	value string
+	// secret is set for fields marked with `env-secret:"true"`, whether or not their value is redacted
+	secret bool
+}
+
+// collectEnvEntries returns the serialized value of every field with an `env` tag in field order, redacting secrets when
+// redact is set. Fields are named by their path from the root struct, such as "Database.URL" or "Upstreams[1].URL".
+func (b *binder) collectEnvEntries(rv reflect.Value, prefix, path string, redact bool) []envEntry {
+	var entries []envEntry
+	rt := rv.Type()
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Field(i)
+		sf := b.field(rt, i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		if isNestedStruct(field.Type()) {
+			entries = append(entries, b.collectEnvEntries(field, b.nestedPrefix(sf, prefix), path+sf.Name+".", redact)...)
+			continue
+		}
+
+		if _, ok := embeddedStructType(sf); ok {
+			if !field.IsNil() {
+				entries = append(entries, b.collectEnvEntries(field.Elem(), b.nestedPrefix(sf, prefix), path+sf.Name+".", redact)...)
+			}
+			continue
+		}
+
+		// only the primary name of a field with fallback names is written
+		envTag := b.envName(sf)
+		if envTag == "" {
+			continue
+		}
+
+		if isStructSlice(field.Type()) {
+			for j := 0; j < field.Len(); j++ {
+				elemPrefix := fmt.Sprintf("%s%s_%d_", prefix, envTag, j)
+				elemPath := fmt.Sprintf("%s%s[%d].", path, sf.Name, j)
+				if elem := reflect.Indirect(field.Index(j)); elem.IsValid() {
+					entries = append(entries, b.collectEnvEntries(elem, elemPrefix, elemPath, redact)...)
+				}
+			}
+			continue
+		}
+
+		if isStructMap(field.Type()) {
+			keys := field.MapKeys()
+			sort.Slice(keys, func(a, b int) bool { return keys[a].String() < keys[b].String() })
+			for _, key := range keys {
+				elemPrefix := fmt.Sprintf("%s%s_%s_", prefix, envTag, key.String())
+				elemPath := fmt.Sprintf("%s%s[%s].", path, sf.Name, key.String())
+				if elem := reflect.Indirect(field.MapIndex(key)); elem.IsValid() {
+					entries = append(entries, b.collectEnvEntries(elem, elemPrefix, elemPath, redact)...)
+				}
+			}
+			continue
+		}
+
+		if isIndexedSlice(sf) {
+			secret := getTag(sf, ENV_SECRET_TAG) == "true"
+			for j := 0; j < field.Len(); j++ {
+				value := formatFieldValue(field.Index(j), sf)
+				if redact && secret {
+					value = REDACTED_VALUE
+				}
+				entries = append(entries, envEntry{key: indexedKey(prefix+envTag, j), name: fmt.Sprintf("%s%s[%d]", path, sf.Name, j), value: value, secret: secret})
+			}
+			continue
+		}
+
+		value := formatFieldValue(field, sf)
+		secret := getTag(sf, ENV_SECRET_TAG) == "true"
+		if redact && secret {
+			value = REDACTED_VALUE
+		}
+		entries = append(entries, envEntry{key: prefix + envTag, name: path + sf.Name, value: value, secret: secret})
+	}
+	return entries
+}
+
+// formatFieldValue formats the value of a field so that binding the result into a field with the same tags yields the
+// same value, mirroring setFieldValue. For example, a time.Duration is written as 1m30s, a time.Time in the layout of
+// its `env-layout` tag and an integer in the base of its `env-base` tag.
+func formatFieldValue(field reflect.Value, structField reflect.StructField) string {
+	if isAtomicType(field.Type()) || isValueType(field.Type()) {
+		return formatFieldValue(addressable(field).Addr().MethodByName("Load").Call(nil)[0], structField)
+	}
+
+	if hasParser(field.Type()) {
+		return fmt.Sprint(field.Interface())
+	}
+
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return ""
+		}
+		return formatFieldValue(field.Elem(), structField)
+	}
+
+	switch {
+	case isFlagValue(field.Type()):
+		return addressable(field).Addr().Interface().(flag.Value).String()
+	case field.Type() == timeType:
+		return formatTimeValue(field.Interface().(time.Time), getTag(structField, ENV_LAYOUT_TAG))
+	case field.Type() == certificateType:
+		cert := field.Interface().(x509.Certificate)
+		return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	case field.Type() == urlType:
+		u := field.Interface().(url.URL)
+		return u.String()
+	case isTextUnmarshaler(field.Type()) && reflect.PointerTo(field.Type()).Implements(textMarshalerType):
+		text, err := addressable(field).Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(text)
+	case field.Type() == rawMessageType:
+		return string(field.Bytes())
+	case field.Type() == bytesType && getTag(structField, ENV_ENCODING_TAG) == "hex":
+		return hex.EncodeToString(field.Bytes())
+	case field.Type() == bytesType && getTag(structField, ENV_FORMAT_TAG) == "pem":
+		// the type of the original block is not kept, which does not matter to setPEMBytesField
+		return string(pem.EncodeToMemory(&pem.Block{Type: "DATA", Bytes: field.Bytes()}))
+	}
+
+	switch field.Kind() {
+	case reflect.Slice:
+		if getTag(structField, ENV_FORMAT_TAG) == "json" {
+			return formatJSONValue(field)
+		}
+		return formatSliceValue(field, structField, getSeparator(structField))
+	case reflect.Map:
+		if getTag(structField, ENV_FORMAT_TAG) == "json" {
+			return formatJSONValue(field)
+		}
+		return formatMapValue(field, structField)
+	case reflect.Interface:
+		if field.IsNil() {
+			return ""
+		}
+		if str, ok := field.Interface().(string); ok {
+			return str
+		}
+		return formatJSONValue(field)
+	}
+
+	// a unit that does not apply to the kind of the field falls through to the plain formatting of the field
+	switch unit := getTag(structField, ENV_UNIT_TAG); {
+	case unit == "percent" && (field.Kind() == reflect.Float32 || field.Kind() == reflect.Float64):
+		return strconv.FormatFloat(field.Float()*100, 'f', -1, 64) + "%"
+	case unit == "bytes" && field.CanInt():
+		return strconv.FormatInt(field.Int(), 10)
+	case unit == "bytes" && field.CanUint():
+		return strconv.FormatUint(field.Uint(), 10)
+	}
+
+	if field.Type() == durationType {
+		if getTag(structField, ENV_DURATION_FORMAT_TAG) == "iso8601" {
+			return formatISODuration(time.Duration(field.Int()))
+		}
+		return time.Duration(field.Int()).String()
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(field.Int(), formatBase(structField))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(field.Uint(), formatBase(structField))
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(field.Float(), 'g', -1, field.Type().Bits())
+	case reflect.Bool:
+		if getTag(structField, ENV_BOOL_MODE_TAG) == "numeric" {
+			if field.Bool() {
+				return "1"
+			}
+			return "0"
+		}
+		return strconv.FormatBool(field.Bool())
+	}
+	return fmt.Sprint(field.Interface())
+}
+
+// addressable returns v, or an addressable copy of it when v cannot be addressed, such as a field of a struct passed
+// by value, so that methods with pointer receivers can be called
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+	cp := reflect.New(v.Type()).Elem()
+	cp.Set(v)
+	return cp
+}
+
+// formatTimeValue formats a time in the layout of an `env-layout` tag. The default layout is written with fractional
+// seconds, which time.Parse accepts, so that no precision is lost.
+func formatTimeValue(t time.Time, layout string) string {
+	switch layout {
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10)
+	case "unixmilli":
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	case "":
+		if DEFAULT_TIME_LAYOUT == time.RFC3339 {
+			return t.Format(time.RFC3339Nano)
+		}
+		return t.Format(DEFAULT_TIME_LAYOUT)
+	}
+	return t.Format(layout)
+}
+
+// formatBase returns the base to format an integer field in. A base of 0 detects the base from the prefix of the value
+// when parsing, so such fields are written in base 10.
+func formatBase(structField reflect.StructField) int {
+	base, err := getBase(structField)
+	if err != nil || base == 0 {
+		return 10
+	}
+	return base
+}
+
+// formatJSONValue encodes a value as JSON, for fields that are decoded from JSON
+func formatJSONValue(field reflect.Value) string {
+	data, err := json.Marshal(field.Interface())
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// formatSliceValue formats the elements of a slice joined with separator. With `env-quoted:"true"` the elements are
+// written as a CSV record, so that elements containing the separator are quoted.
+func formatSliceValue(field reflect.Value, structField reflect.StructField, separator string) string {
+	elems := make([]string, 0, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		elems = append(elems, formatFieldValue(field.Index(i), structField))
+	}
+	return joinElements(elems, structField, separator)
+}
+
+// joinElements joins formatted elements with separator, as a CSV record when the field is tagged with `env-quoted`
+func joinElements(elems []string, structField reflect.StructField, separator string) string {
+	comma, size := utf8.DecodeRuneInString(separator)
+	if getTag(structField, ENV_QUOTED_TAG) != "true" || size != len(separator) {
+		return strings.Join(elems, separator)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = comma
+	if err := w.Write(elems); err != nil {
+		return strings.Join(elems, separator)
+	}
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// formatMapValue formats a map as key=value pairs sorted by key, so that the output is reproducible. Pairs are joined
+// with the separator of the field and slice values with its value separator, as setMapField expects.
+func formatMapValue(field reflect.Value, structField reflect.StructField) string {
+	keys := make([]string, 0, field.Len())
+	values := make(map[string]string, field.Len())
+	iter := field.MapRange()
+	for iter.Next() {
+		key := formatFieldValue(iter.Key(), structField)
+		value := formatFieldValue(iter.Value(), structField)
+		if iter.Value().Kind() == reflect.Slice {
+			value = formatSliceValue(iter.Value(), structField, getValueSeparator(structField))
+		}
+		keys = append(keys, key)
+		values[key] = value
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+values[key])
+	}
+	return joinElements(pairs, structField, getSeparator(structField))
+}
+
+// quoteEnvValue quotes the value when it contains characters that would otherwise be misread in a dotenv file
+func quoteEnvValue(value string) string {
+	if strings.ContainsAny(value, " \t\r\n\"'#\\") {
+		return strconv.Quote(value)
+	}
+	return value
+}
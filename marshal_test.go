@@ -0,0 +1,263 @@
+package ectoenv
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMarshalEnv(t *testing.T) {
+	type Database struct {
+		URL      string `env:"DATABASE_URL"`
+		Password string `env:"DATABASE_PASSWORD" env-secret:"true"`
+	}
+	type Upstream struct {
+		URL string `env:"URL"`
+	}
+	type Config struct {
+		Host      string   `env:"HOST"`
+		Port      int      `env:"PORT"`
+		Debug     bool     `env:"DEBUG"`
+		Tags      []string `env:"TAGS"`
+		Greeting  string   `env:"GREETING"`
+		Untagged  string
+		Database  Database
+		Upstreams []Upstream `env:"UPSTREAM"`
+	}
+
+	config := Config{
+		Host:      "localhost",
+		Port:      8080,
+		Debug:     true,
+		Tags:      []string{"a", "b"},
+		Greeting:  "hello world",
+		Untagged:  "ignored",
+		Database:  Database{URL: "postgres://db", Password: "hunter2"},
+		Upstreams: []Upstream{{URL: "http://a"}, {URL: "http://b"}},
+	}
+
+	data, err := MarshalEnv(&config)
+	if err != nil {
+		t.Fatalf("MarshalEnv() error = %v", err)
+	}
+
+	expected := `HOST=localhost
+PORT=8080
+DEBUG=true
+TAGS=a,b
+GREETING="hello world"
+DATABASE_URL=postgres://db
+DATABASE_PASSWORD=****
+UPSTREAM_0_URL=http://a
+UPSTREAM_1_URL=http://b
+`
+	if string(data) != expected {
+		t.Errorf("MarshalEnv() got = %q, want %q", data, expected)
+	}
+}
+
+func TestWriteEnvFile(t *testing.T) {
+	type Config struct {
+		Token string `env:"TOKEN" env-secret:"true"`
+	}
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := WriteEnvFile(path, Config{Token: "secret"}); err != nil {
+		t.Fatalf("WriteEnvFile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(data) != "TOKEN=****\n" {
+		t.Errorf("WriteEnvFile() wrote %q, want %q", data, "TOKEN=****\n")
+	}
+}
+
+func TestMarshalEnvInvalidInput(t *testing.T) {
+	if _, err := MarshalEnv(42); err == nil {
+		t.Errorf("MarshalEnv() expected error for non-struct input, got nil")
+	}
+}
+
+func TestMarshalEnvMapsAreSorted(t *testing.T) {
+	type Config struct {
+		Labels map[string]string   `env:"LABELS"`
+		Routes map[string][]string `env:"ROUTES"`
+		Limits map[string]int      `env:"LIMITS"`
+	}
+
+	config := Config{
+		Labels: map[string]string{"team": "core", "env": "prod", "app": "api", "zone": "a"},
+		Routes: map[string][]string{"web": {"c"}, "api": {"a", "b"}},
+		Limits: map[string]int{"b": 2, "a": 1, "c": 3},
+	}
+
+	expected := `LABELS=app=api,env=prod,team=core,zone=a
+ROUTES=api=a|b,web=c
+LIMITS=a=1,b=2,c=3
+`
+	// map iteration order is random, so marshal several times to catch unstable output
+	for i := 0; i < 20; i++ {
+		data, err := MarshalEnv(&config)
+		if err != nil {
+			t.Fatalf("MarshalEnv() error = %v", err)
+		}
+		if string(data) != expected {
+			t.Fatalf("MarshalEnv() got = %q, want %q", data, expected)
+		}
+	}
+
+	var bound Config
+	if err := BindEnvFromMap(&bound, map[string]string{
+		"LABELS": "app=api,env=prod,team=core,zone=a",
+		"ROUTES": "api=a|b,web=c",
+		"LIMITS": "a=1,b=2,c=3",
+	}); err != nil {
+		t.Fatalf("BindEnvFromMap() error = %v", err)
+	}
+	if !reflect.DeepEqual(bound, config) {
+		t.Errorf("BindEnvFromMap() got = %v, want %v", bound, config)
+	}
+}
+
+func TestMarshalEnvRoundTrip(t *testing.T) {
+	type Config struct {
+		String      string                 `env:"STRING"`
+		Int         int                    `env:"INT"`
+		Int8        int8                   `env:"INT8"`
+		Hex         uint32                 `env:"HEX" env-base:"16"`
+		Uint64      uint64                 `env:"UINT64"`
+		Float       float64                `env:"FLOAT"`
+		Bool        bool                   `env:"BOOL"`
+		Duration    time.Duration          `env:"DURATION"`
+		Time        time.Time              `env:"TIME"`
+		Date        time.Time              `env:"DATE" env-layout:"2006-01-02"`
+		Epoch       time.Time              `env:"EPOCH" env-layout:"unix"`
+		Pointer     *int                   `env:"POINTER"`
+		Strings     []string               `env:"STRINGS"`
+		Quoted      []string               `env:"QUOTED" env-quoted:"true"`
+		Lines       []string               `env:"LINES" env-separator:"\n"`
+		Durations   []time.Duration        `env:"DURATIONS" env-separator:";"`
+		JSON        []string               `env:"JSON" env-format:"json"`
+		Labels      map[string]string      `env:"LABELS"`
+		Routes      map[string][]string    `env:"ROUTES" env-value-separator:"+"`
+		Plugin      map[string]interface{} `env:"PLUGIN" env-format:"json"`
+		Any         interface{}            `env:"ANY"`
+		Bytes       int                    `env:"BYTES" env-unit:"bytes"`
+		Bytes64     int64                  `env:"BYTES64" env-unit:"bytes"`
+		UBytes64    uint64                 `env:"UBYTES64" env-unit:"bytes"`
+		Percent     float64                `env:"PERCENT" env-unit:"percent"`
+		Addr        netip.Addr             `env:"ADDR"`
+		Prefixes    []netip.Prefix         `env:"PREFIXES"`
+		List        testList               `env:"LIST"`
+		Counter     atomic.Int64           `env:"COUNTER"`
+		Timeout     Value[time.Duration]   `env:"TIMEOUT"`
+		Certificate x509.Certificate       `env:"CERTIFICATE"`
+		DER         []byte                 `env:"DER" env-format:"pem"`
+		Key         []byte                 `env:"KEY" env-encoding:"hex"`
+	}
+
+	pointer := 42
+	block, _ := pem.Decode([]byte(testCertificatePEM))
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate() error = %v", err)
+	}
+
+	config := Config{
+		String:      "hello world",
+		Int:         -8080,
+		Int8:        -8,
+		Hex:         0xff,
+		Uint64:      18446744073709551615,
+		Float:       0.1,
+		Bool:        true,
+		Duration:    90 * time.Second,
+		Time:        time.Date(2024, 5, 1, 12, 30, 0, 500, time.UTC),
+		Date:        time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		Epoch:       time.Unix(1714566600, 0),
+		Pointer:     &pointer,
+		Strings:     []string{"a", "b"},
+		Quoted:      []string{"a,b", `say "hi"`},
+		Lines:       []string{"first line", "second line"},
+		Durations:   []time.Duration{time.Millisecond, time.Hour},
+		JSON:        []string{"x,y", "z"},
+		Labels:      map[string]string{"env": "prod", "team": "core"},
+		Routes:      map[string][]string{"api": {"a", "b"}, "web": {"c"}},
+		Plugin:      map[string]interface{}{"retries": float64(3), "tls": map[string]interface{}{"enabled": true}},
+		Any:         []interface{}{"a", float64(1)},
+		Bytes:       10000000,
+		Bytes64:     1 << 40,
+		UBytes64:    1 << 62,
+		Percent:     0.25,
+		Addr:        netip.MustParseAddr("fe80::1%eth0"),
+		Prefixes:    []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("::1/128")},
+		List:        testList{"one"},
+		Certificate: *cert,
+		DER:         block.Bytes,
+		Key:         []byte{0xde, 0xad, 0xbe, 0xef},
+	}
+	config.Counter.Store(7)
+	config.Timeout.Store(time.Minute)
+
+	data, err := MarshalEnv(&config)
+	if err != nil {
+		t.Fatalf("MarshalEnv() error = %v", err)
+	}
+	envVars, err := parseEnvFile(data)
+	if err != nil {
+		t.Fatalf("parseEnvFile() error = %v", err)
+	}
+
+	var bound Config
+	if err := BindEnvFromMap(&bound, envVars); err != nil {
+		t.Fatalf("BindEnvFromMap() error = %v\n%s", err, data)
+	}
+
+	if bound.Counter.Load() != 7 || bound.Timeout.Load() != time.Minute {
+		t.Errorf("round trip got Counter = %d, Timeout = %s", bound.Counter.Load(), bound.Timeout.Load())
+	}
+	if !bound.Time.Equal(config.Time) || !bound.Date.Equal(config.Date) || !bound.Epoch.Equal(config.Epoch) {
+		t.Errorf("round trip got times %s, %s, %s", bound.Time, bound.Date, bound.Epoch)
+	}
+	if !bound.Certificate.Equal(&config.Certificate) {
+		t.Errorf("round trip got a different certificate")
+	}
+
+	// compare the remaining fields, which support reflect.DeepEqual
+	bound.Counter, config.Counter = atomic.Int64{}, atomic.Int64{}
+	bound.Timeout, config.Timeout = Value[time.Duration]{}, Value[time.Duration]{}
+	bound.Time, bound.Date, bound.Epoch = config.Time, config.Date, config.Epoch
+	bound.Certificate = config.Certificate
+	if !reflect.DeepEqual(&bound, &config) {
+		t.Errorf("round trip got = %+v, want %+v\n%s", &bound, &config, data)
+	}
+}
+
+func TestMarshalEnvFormatsTypes(t *testing.T) {
+	type Config struct {
+		Timeout time.Duration `env:"TIMEOUT"`
+		Date    time.Time     `env:"DATE" env-layout:"2006-01-02"`
+		Mask    uint8         `env:"MASK" env-base:"2"`
+		Legacy  []bool        `env:"LEGACY" env-bool-mode:"numeric"`
+	}
+
+	config := Config{Timeout: 30 * time.Second, Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Mask: 5, Legacy: []bool{true, false}}
+	data, err := MarshalEnv(config)
+	if err != nil {
+		t.Fatalf("MarshalEnv() error = %v", err)
+	}
+
+	expected := "TIMEOUT=30s\nDATE=2024-05-01\nMASK=101\nLEGACY=1,0\n"
+	if string(data) != expected {
+		t.Errorf("MarshalEnv() got = %q, want %q", data, expected)
+	}
+}
@@ -0,0 +1,103 @@
+package ectoenv
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// DEFAULT_NESTED_SEPARATOR is the separator used by WithFlattenedNames to join the names of nested fields
+var DEFAULT_NESTED_SEPARATOR = "__"
+
+// NameCase controls how variable names are derived from field names
+type NameCase int
+
+const (
+	// UpperSnake derives MAX_RETRIES from MaxRetries
+	UpperSnake NameCase = iota
+	// LowerSnake derives max_retries from MaxRetries
+	LowerSnake
+	// Kebab derives max-retries from MaxRetries
+	Kebab
+)
+
+// format derives a variable name from a field name
+func (c NameCase) format(name string) string {
+	switch c {
+	case LowerSnake:
+		return strings.ToLower(toUpperSnake(name))
+	case Kebab:
+		return strings.ReplaceAll(strings.ToLower(toUpperSnake(name)), "_", "-")
+	}
+	return toUpperSnake(name)
+}
+
+// envNames returns the candidate names of the variable for the field, without any prefix, in the order they should be
+// tried. The `env` tag may list several comma separated names, e.g. `env:"DATABASE_URL,DB_URL"`. When names are
+// flattened, the name of an untagged field is derived from its field name. No names means the field is not bound.
+func (b *binder) envNames(field reflect.StructField) []string {
+	names := envTagNames(field)
+	if len(names) == 0 && field.Tag.Get(canonicalEnvTag) != "-" && b.nestedSeparator != "" {
+		return []string{b.nameCase.format(field.Name)}
+	}
+	return names
+}
+
+// envName returns the primary name of the variable for the field, without any prefix. An empty name means the field is
+// not bound.
+func (b *binder) envName(field reflect.StructField) string {
+	names := b.envNames(field)
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+// nestedPrefix returns the prefix for the fields of a nested struct field. An `env-prefix` tag is appended to the
+// parent's prefix, or replaces it when it starts with ABSOLUTE_PREFIX_MARKER. Otherwise nested structs share the prefix
+// of their parent unless names are flattened, in which case the name of the field is appended.
+func (b *binder) nestedPrefix(field reflect.StructField, prefix string) string {
+	if nested, ok := tagPrefix(field, prefix); ok {
+		return nested
+	}
+
+	if b.nestedSeparator == "" || field.Anonymous {
+		return prefix
+	}
+
+	name := b.envName(field)
+	if name == "" {
+		return prefix
+	}
+	return prefix + name + b.nestedSeparator
+}
+
+// tagPrefix returns the prefix given by the field's `env-prefix` tag and whether the field has one
+func tagPrefix(field reflect.StructField, prefix string) (string, bool) {
+	tag := getTag(field, ENV_PREFIX_TAG)
+	if tag == "" {
+		return "", false
+	}
+	if absolute, ok := strings.CutPrefix(tag, ABSOLUTE_PREFIX_MARKER); ok {
+		return absolute, true
+	}
+	return prefix + tag, true
+}
+
+// toUpperSnake converts a Go identifier such as MaxRetries or HTTPServer to upper snake case, e.g. MAX_RETRIES or
+// HTTP_SERVER
+func toUpperSnake(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				sb.WriteRune('_')
+			}
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+	}
+	return sb.String()
+}
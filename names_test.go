@@ -0,0 +1,158 @@
+package ectoenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToUpperSnake(t *testing.T) {
+	tests := map[string]string{
+		"Port":       "PORT",
+		"MaxRetries": "MAX_RETRIES",
+		"HTTPServer": "HTTP_SERVER",
+		"APIKey":     "API_KEY",
+		"ID":         "ID",
+		"V2Endpoint": "V2_ENDPOINT",
+	}
+
+	for input, expected := range tests {
+		if got := toUpperSnake(input); got != expected {
+			t.Errorf("toUpperSnake(%s) got = %v, want %v", input, got, expected)
+		}
+	}
+}
+
+func TestBindEnvWithFlattenedNames(t *testing.T) {
+	type TLS struct {
+		Enabled bool
+	}
+	type Server struct {
+		Port    int
+		Host    string `env:"HOSTNAME"`
+		TLS     TLS
+		Skipped string `env:"-"`
+	}
+	type Config struct {
+		Debug  bool
+		Server Server
+	}
+
+	m := map[string]string{
+		"APP_DEBUG":                "true",
+		"APP_SERVER__PORT":         "8080",
+		"APP_SERVER__HOSTNAME":     "localhost",
+		"APP_SERVER__TLS__ENABLED": "true",
+		"APP_SERVER__SKIPPED":      "value",
+	}
+
+	var config Config
+	err := BindEnvWith(&config, WithPrefix("APP_"), WithFlattenedNames(""), withLookupMap(m))
+	if err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+
+	expected := Config{
+		Debug: true,
+		Server: Server{
+			Port: 8080,
+			Host: "localhost",
+			TLS:  TLS{Enabled: true},
+		},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvWith() got = %v, want %v", config, expected)
+	}
+
+	config = Config{}
+	err = BindEnvWith(&config, WithFlattenedNames("."), withLookupMap(map[string]string{"SERVER.PORT": "9090"}))
+	if err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+	if config.Server.Port != 9090 {
+		t.Errorf("BindEnvWith() got = %v, want %v", config.Server.Port, 9090)
+	}
+}
+
+func TestBindEnvWithNameCase(t *testing.T) {
+	type Server struct {
+		MaxRetries int
+		Host       string `env:"HOSTNAME"`
+	}
+	type Config struct {
+		Server Server
+	}
+
+	tests := []struct {
+		name     string
+		nameCase NameCase
+		m        map[string]string
+	}{
+		{
+			name:     "UpperSnake",
+			nameCase: UpperSnake,
+			m:        map[string]string{"SERVER__MAX_RETRIES": "3", "SERVER__HOSTNAME": "localhost"},
+		},
+		{
+			name:     "LowerSnake",
+			nameCase: LowerSnake,
+			m:        map[string]string{"server__max_retries": "3", "server__HOSTNAME": "localhost"},
+		},
+		{
+			name:     "Kebab",
+			nameCase: Kebab,
+			m:        map[string]string{"server__max-retries": "3", "server__HOSTNAME": "localhost"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvWith(&config, WithFlattenedNames(""), WithNameCase(tt.nameCase), withLookupMap(tt.m))
+			if err != nil {
+				t.Fatalf("BindEnvWith() error = %v", err)
+			}
+
+			expected := Config{Server: Server{MaxRetries: 3, Host: "localhost"}}
+			if !reflect.DeepEqual(config, expected) {
+				t.Errorf("BindEnvWith() got = %v, want %v", config, expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvNestedPrefix(t *testing.T) {
+	type Logging struct {
+		Level string `env:"LEVEL"`
+	}
+	type Database struct {
+		URL     string  `env:"URL"`
+		Logging Logging `env-prefix:"LOG_"`
+	}
+	type Config struct {
+		Database Database `env-prefix:"DB_"`
+		Logging  Logging  `env-prefix:"/LOG_"`
+		Audit    Logging  `env:"AUDIT,prefix=/"`
+	}
+
+	m := map[string]string{
+		"APP_DB_URL":       "postgres://db",
+		"APP_DB_LOG_LEVEL": "debug",
+		"LOG_LEVEL":        "info",
+		"LEVEL":            "warn",
+		"APP_LOG_LEVEL":    "error",
+	}
+
+	var config Config
+	if err := BindEnvWith(&config, WithPrefix("APP_"), withLookupMap(m)); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+
+	expected := Config{
+		Database: Database{URL: "postgres://db", Logging: Logging{Level: "debug"}},
+		Logging:  Logging{Level: "info"},
+		Audit:    Logging{Level: "warn"},
+	}
+	if config != expected {
+		t.Errorf("BindEnvWith() got = %v, want %v", config, expected)
+	}
+}
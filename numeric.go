@@ -0,0 +1,56 @@
+package ectoenv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ENV_STRICT_NUMERIC_TAG is the tag used to opt a numeric field into the format check of WithStrictNumericFormat
+var ENV_STRICT_NUMERIC_TAG = "env-strict-numeric"
+
+// checkNumericFormat returns an error if the value of an integer or float field tagged with `env-strict-numeric:"true"`
+// is written in the format of the other kind. A float value must contain a decimal point or an exponent, or be one of
+// inf, infinity or nan, so "3" is rejected while "3.0" and "3e0" are accepted. An integer value must not contain a
+// decimal point, so "3.0" is rejected with a clearer error than the one of strconv. Other fields are not checked.
+func checkNumericFormat(field reflect.Value, structField reflect.StructField, envValue string) error {
+	if getTag(structField, ENV_STRICT_NUMERIC_TAG) != "true" || field.Type() == durationType || getTag(structField, ENV_UNIT_TAG) != "" {
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if !isFloatLiteral(envValue) {
+			return fmt.Errorf("unable to set value for field %s. %s is an integer, expected a float such as %s", structField.Name, envValue, floatSuggestion(envValue))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if strings.Contains(envValue, ".") {
+			return fmt.Errorf("unable to set value for field %s. %s is a float, expected an integer", structField.Name, envValue)
+		}
+	}
+	return nil
+}
+
+// isFloatLiteral reports whether the value is written as a float rather than an integer
+func isFloatLiteral(envValue string) bool {
+	value := strings.ToLower(strings.TrimLeft(envValue, "+-"))
+	switch value {
+	case "inf", "infinity", "nan":
+		return true
+	}
+
+	if strings.HasPrefix(value, "0x") {
+		return strings.ContainsAny(value, ".p")
+	}
+	return strings.ContainsAny(value, ".e")
+}
+
+// floatSuggestion returns the float literal equal to the integer value, such as 3.0 for 3. A hex mantissa needs a binary
+// exponent to parse as a float, so 0x1e becomes 0x1ep0.
+func floatSuggestion(envValue string) string {
+	if strings.HasPrefix(strings.ToLower(strings.TrimLeft(envValue, "+-")), "0x") {
+		return envValue + "p0"
+	}
+	return envValue + ".0"
+}
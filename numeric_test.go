@@ -0,0 +1,106 @@
+package ectoenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBindEnvStrictNumericFormat(t *testing.T) {
+	type Config struct {
+		Ratio   float64 `env:"RATIO" env-strict-numeric:"true"`
+		Count   int     `env:"COUNT,strict-numeric"`
+		Loose   float64 `env:"LOOSE"`
+		Workers uint    `env:"WORKERS" env-strict-numeric:"true"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		disabled bool
+		expected Config
+		wantErr  string
+	}{
+		{
+			name:     "Valid",
+			envVars:  map[string]string{"RATIO": "0.5", "COUNT": "3", "LOOSE": "3", "WORKERS": "4"},
+			expected: Config{Ratio: 0.5, Count: 3, Loose: 3, Workers: 4},
+		},
+		{
+			name:     "Exponent",
+			envVars:  map[string]string{"RATIO": "5e-1"},
+			expected: Config{Ratio: 0.5},
+		},
+		{
+			name:     "Hex float",
+			envVars:  map[string]string{"RATIO": "0x1p-1"},
+			expected: Config{Ratio: 0.5},
+		},
+		{
+			name:    "Integer for float",
+			envVars: map[string]string{"RATIO": "3"},
+			wantErr: "unable to set value for field Ratio. 3 is an integer, expected a float such as 3.0",
+		},
+		{
+			name:    "Hex integer for float",
+			envVars: map[string]string{"RATIO": "0x1e"},
+			wantErr: "unable to set value for field Ratio. 0x1e is an integer, expected a float such as 0x1ep0",
+		},
+		{
+			name:    "Float for int",
+			envVars: map[string]string{"COUNT": "3.0"},
+			wantErr: "unable to set value for field Count. 3.0 is a float, expected an integer",
+		},
+		{
+			name:    "Float for uint",
+			envVars: map[string]string{"WORKERS": "4.5"},
+			wantErr: "unable to set value for field Workers. 4.5 is a float, expected an integer",
+		},
+		{
+			name:     "Disabled",
+			envVars:  map[string]string{"RATIO": "3"},
+			disabled: true,
+			expected: Config{Ratio: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvWith(&config, withLookupMap(tt.envVars), WithStrictNumericFormat(!tt.disabled))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvWith() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvWith() error = %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("BindEnvWith() got = %+v, want %+v", config, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsFloatLiteral(t *testing.T) {
+	tests := map[string]bool{
+		"1.5":   true,
+		"1.":    true,
+		".5":    true,
+		"1e3":   true,
+		"-1E3":  true,
+		"+Inf":  true,
+		"NaN":   true,
+		"0x1p4": true,
+		"1":     false,
+		"-10":   false,
+		"0x1e":  false,
+		"1_000": false,
+	}
+	for value, expected := range tests {
+		if got := isFloatLiteral(value); got != expected {
+			t.Errorf("isFloatLiteral(%q) = %v, want %v", value, got, expected)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package ectoenv
+
+import "reflect"
+
+// FieldSource describes where the value of a field came from
+type FieldSource string
+
+const (
+	// SourceEnv is a value read from one of the field's variables
+	SourceEnv FieldSource = "env"
+	// SourceDefaultFrom is a value read from the variable named by the field's `env-default-from` tag
+	SourceDefaultFrom FieldSource = "default-from"
+	// SourceDefault is the value of the field's `env-default` tag
+	SourceDefault FieldSource = "default"
+	// SourceDefaultsFile is a value read from the file given to WithDefaultsFile
+	SourceDefaultsFile FieldSource = "defaults-file"
+	// SourceUnset means no value was found, so the field was left unchanged
+	SourceUnset FieldSource = "unset"
+)
+
+// FieldInfo describes how a field was bound, and is passed to the WithFieldObserver callback
+type FieldInfo struct {
+	// Path is the path of the field from the root struct, such as "Database.URL" or "Upstreams[1].URL"
+	Path string
+	// Key is the variable the value was read from, or the field's primary variable when the value is a default or unset
+	Key string
+	// Source is where the value came from
+	Source FieldSource
+	// Err is the error returned when the value could not be bound, or nil if it was bound successfully
+	Err error
+}
+
+// observe passes the result of binding a field to the field observer, if one is registered
+func (b *binder) observe(field reflect.StructField, key string, source FieldSource, err error) {
+	if b.observer == nil {
+		return
+	}
+	b.observer(FieldInfo{Path: b.path + field.Name, Key: key, Source: source, Err: err})
+}
+
+// enterPath appends name to the path of the fields being bound, returning a function that restores the previous path.
+// The path is only tracked when a field observer is registered or a refresh loop tracks the values of fields.
+func (b *binder) enterPath(name string) func() {
+	if b.observer == nil && b.lastSeen == nil {
+		return func() {}
+	}
+	path := b.path
+	b.path += name
+	return func() { b.path = path }
+}
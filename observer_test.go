@@ -0,0 +1,57 @@
+package ectoenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBindEnvWithFieldObserver(t *testing.T) {
+	type Upstream struct {
+		URL string `env:"URL"`
+	}
+	type Database struct {
+		URL string `env:"DATABASE_URL" env-default-from:"DEFAULT_DATABASE_URL"`
+	}
+	type Config struct {
+		Host      string `env:"HOST,LEGACY_HOST"`
+		Port      int    `env:"PORT" env-default:"8080"`
+		Retries   int    `env:"RETRIES"`
+		Timeout   string `env:"TIMEOUT"`
+		Database  Database
+		Upstreams []Upstream `env:"UPSTREAM"`
+	}
+
+	var infos []FieldInfo
+	var config Config
+	err := BindEnvWith(&config, withLookupMap(map[string]string{
+		"LEGACY_HOST":          "localhost",
+		"RETRIES":              "many",
+		"DEFAULT_DATABASE_URL": "postgres://db",
+		"UPSTREAM_0_URL":       "http://a",
+	}), WithLenientParsing(true), WithFieldObserver(func(info FieldInfo) {
+		infos = append(infos, info)
+	}))
+	if err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+
+	if len(infos) != 6 {
+		t.Fatalf("WithFieldObserver() observed %d fields, want 6: %+v", len(infos), infos)
+	}
+	if infos[2].Err == nil {
+		t.Errorf("WithFieldObserver() expected an error for Retries, got nil")
+	}
+	infos[2].Err = nil
+
+	expected := []FieldInfo{
+		{Path: "Host", Key: "LEGACY_HOST", Source: SourceEnv},
+		{Path: "Port", Key: "PORT", Source: SourceDefault},
+		{Path: "Retries", Key: "RETRIES", Source: SourceEnv},
+		{Path: "Timeout", Key: "TIMEOUT", Source: SourceUnset},
+		{Path: "Database.URL", Key: "DEFAULT_DATABASE_URL", Source: SourceDefaultFrom},
+		{Path: "Upstreams[0].URL", Key: "UPSTREAM_0_URL", Source: SourceEnv},
+	}
+	if !reflect.DeepEqual(infos, expected) {
+		t.Errorf("WithFieldObserver() got = %+v, want %+v", infos, expected)
+	}
+}
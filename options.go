@@ -0,0 +1,489 @@
+package ectoenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Option configures how BindEnvWith binds a struct
+type Option func(*binder)
+
+// BindEnvWith is like BindEnv but accepts options that configure how the struct is bound.
+// v: a non-nil pointer to a struct
+// opts: the options to apply
+// returns: an error if the provided value is not a non-nil pointer to a struct or if the value of an environment variable
+// cannot be converted to the type of its field
+func BindEnvWith(v interface{}, opts ...Option) error {
+	rv, err := validateInput(v)
+	if err != nil {
+		return err
+	}
+
+	return newBinder(opts...).bind(rv)
+}
+
+// BindEnvInto allocates a value of type T, binds it as BindEnvWith does and returns it, so that a config can be created
+// in one statement: cfg, err := BindEnvInto[Config](WithPrefix("APP_")).
+// opts: the options to apply
+// returns: the bound value, or the zero value and an error if T is not a struct or if the value of an environment
+// variable cannot be converted to the type of its field
+func BindEnvInto[T any](opts ...Option) (T, error) {
+	var v T
+	if err := BindEnvWith(&v, opts...); err != nil {
+		var zero T
+		return zero, err
+	}
+	return v, nil
+}
+
+// BindEnvMulti binds each of several structs as BindEnvWith does, with the same options, so that related configs such as
+// those of logging, the database and the HTTP server are bound in one statement with a consistent prefix and tag names.
+// Every struct is bound even when an earlier one fails, and the errors are returned together.
+// opts: the options to apply to every struct
+// vs: non-nil pointers to structs
+// returns: the errors of the structs that failed to bind joined with errors.Join, each naming the type of its struct, or
+// nil if every struct was bound
+func BindEnvMulti(opts []Option, vs ...interface{}) error {
+	var errs []error
+	for _, v := range vs {
+		if err := BindEnvWith(v, opts...); err != nil {
+			errs = append(errs, fmt.Errorf("unable to bind %T: %w", v, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithRequireAll treats every field with an `env` tag and no `env-default` as required, as if it were tagged with
+// `env-required:"true"`. All missing variables are reported together.
+func WithRequireAll(requireAll bool) Option {
+	return func(b *binder) {
+		b.requireAll = requireAll
+	}
+}
+
+// WithOnRefresh registers a callback that BindEnvWithAutoRefresh invokes at the end of every refresh, after the struct
+// has been rebound. The callback runs whether or not any values changed and whether or not the rebind failed.
+func WithOnRefresh(fn func()) Option {
+	return func(b *binder) {
+		b.onRefresh = fn
+	}
+}
+
+// WithErrorHandler registers a callback that receives errors that do not stop a bind, such as the parse errors ignored by
+// WithLenientParsing. BindEnvWithAutoRefresh also passes refresh failures to the callback instead of printing them.
+func WithErrorHandler(fn func(error)) Option {
+	return func(b *binder) {
+		b.onError = fn
+	}
+}
+
+// WithLenientParsing treats a value that cannot be parsed as a warning rather than an error. The field's `env-default`
+// is applied instead, or the field is left unchanged when there is no valid default, and the error is passed to the
+// WithErrorHandler callback. Parsing is strict by default.
+func WithLenientParsing(lenient bool) Option {
+	return func(b *binder) {
+		b.lenient = lenient
+	}
+}
+
+// WithErrorMode controls whether binding stops at the first error or sets every field it can and reports all errors. With
+// FailFast, the default, the fields before the failing one are set and the rest are left unchanged. With Collect, every
+// field that binds is set, each failing field keeps its previous value, and the errors are returned joined with
+// errors.Join. In both modes, missing required variables are reported before any field is set.
+func WithErrorMode(mode ErrorMode) Option {
+	return func(b *binder) {
+		b.errorMode = mode
+	}
+}
+
+// WithPrefix prepends prefix to the name of every environment variable, so that with WithPrefix("APP_") a field tagged
+// `env:"PORT"` reads APP_PORT.
+func WithPrefix(prefix string) Option {
+	return func(b *binder) {
+		b.prefix = prefix
+	}
+}
+
+// WithOnlyIfZero binds only the fields that hold their zero value, as reported by reflect.Value.IsZero, so that a struct
+// populated by code or another source keeps its values and the environment fills the gaps. Nested structs are walked
+// field by field, while a non-nil slice or map, including one of structs, is kept whole. A field that already holds a
+// value satisfies `env-required`, since it is not bound; a zero field still requires its variable.
+func WithOnlyIfZero(onlyIfZero bool) Option {
+	return func(b *binder) {
+		b.onlyIfZero = onlyIfZero
+	}
+}
+
+// WithRequirePrefixPresent fails the bind when no variable in the environment starts with the prefix set by WithPrefix,
+// catching a config that was not provided at all even though every field has a default. A bind that also uses
+// WithLookup fails with an error, because the variables of a lookup cannot be listed. Variables set to an empty value
+// count unless WithEmptyAsUnset is used. It has no effect without a prefix.
+func WithRequirePrefixPresent(require bool) Option {
+	return func(b *binder) {
+		b.requirePrefixPresent = require
+	}
+}
+
+// WithFlattenedNames binds every exported field, deriving the names of untagged fields from their field names in upper
+// snake case, and addresses the fields of nested structs by joining the name of the parent field and the name of the
+// child with separator. For example, Server.Port reads SERVER__PORT with the default separator. Fields tagged with
+// `env:"-"` are skipped. An empty separator uses DEFAULT_NESTED_SEPARATOR.
+func WithFlattenedNames(separator string) Option {
+	return func(b *binder) {
+		if separator == "" {
+			separator = DEFAULT_NESTED_SEPARATOR
+		}
+		b.nestedSeparator = separator
+	}
+}
+
+// WithNameCase controls how WithFlattenedNames derives variable names from field names, e.g. MaxRetries becomes
+// MAX_RETRIES with UpperSnake, max_retries with LowerSnake and max-retries with Kebab. The default is UpperSnake. Names
+// given in tags are always used as they are.
+func WithNameCase(nameCase NameCase) Option {
+	return func(b *binder) {
+		b.nameCase = nameCase
+	}
+}
+
+// WithRejectUnknown fails the bind if, after every field has been bound, there are variables starting with prefix that
+// were not read by any field. This catches misspelled or obsolete variables such as APP_PROT instead of APP_PORT.
+func WithRejectUnknown(prefix string) Option {
+	return func(b *binder) {
+		b.rejectUnknownPrefix = prefix
+	}
+}
+
+// WithStrict returns an error as soon as a tagged field with a type that cannot be bound is encountered, rather than
+// silently leaving the field unset
+func WithStrict(strict bool) Option {
+	return func(b *binder) {
+		b.strict = strict
+	}
+}
+
+// WithClamp replaces a value outside the range set by the `env-min` and `env-max` tags with the bound it exceeds, rather
+// than returning an error
+func WithClamp(clamp bool) Option {
+	return func(b *binder) {
+		b.clamp = clamp
+	}
+}
+
+// WithMaxRefreshErrors stops the refresh loops of BindEnvWithAutoRefresh, BindEnvWatch and BindEnvReloadable after n
+// consecutive refreshes fail, rather than retrying forever. The loop reports a terminal error wrapping ErrRefreshStopped
+// and the last failure to the handler registered with WithErrorHandler, or prints it, and then exits; BindEnvWatch also
+// closes its channel. A successful refresh resets the count. The default, 0, never stops.
+func WithMaxRefreshErrors(n int) Option {
+	return func(b *binder) {
+		b.maxRefreshErrors = n
+	}
+}
+
+// WithSilentRefresh stops the refresh loops of BindEnvWithAutoRefresh, BindEnvWatch and BindEnvReloadable from printing
+// failures and recoveries to standard output. Failures are still passed to the handler registered with
+// WithErrorHandler, and BindEnvWatch still reports changes on its channel; without a handler they are discarded.
+func WithSilentRefresh(silent bool) Option {
+	return func(b *binder) {
+		b.silentRefresh = silent
+	}
+}
+
+// WithRefreshErrorInterval controls how often BindEnvWithAutoRefresh reports a refresh failure that repeats on every
+// cycle. A failure is reported when it first occurs or its message changes, and then only once every cycles refreshes
+// until a refresh succeeds. The default is DEFAULT_REFRESH_ERROR_INTERVAL; 1 reports every failure.
+func WithRefreshErrorInterval(cycles int) Option {
+	return func(b *binder) {
+		b.refreshErrorInterval = cycles
+	}
+}
+
+// WithGroup binds only the fields whose `env-group` tag includes group. The fields of a nested struct in the group are
+// all bound, while the fields of an ungrouped nested struct are considered individually. Fields that are not bound are
+// neither checked for required values nor set to their defaults.
+func WithGroup(group string) Option {
+	return func(b *binder) {
+		b.group = group
+	}
+}
+
+// WithUngrouped also binds the fields without an `env-group` tag when binding a group with WithGroup, which is useful
+// for settings shared by every group
+func WithUngrouped(include bool) Option {
+	return func(b *binder) {
+		b.includeUngrouped = include
+	}
+}
+
+// WithDefaultsFile reads defaults from the dotenv file at path, such as a defaults.env kept for each deployment
+// environment. A value from the file is used only when a field has neither a variable set nor an `env-default` tag, so
+// the precedence is: environment, inline default, defaults file. The file is read on every bind, including refreshes.
+func WithDefaultsFile(path string) Option {
+	return func(b *binder) {
+		b.defaultsFile = path
+	}
+}
+
+// WithDetectDuplicates fails the bind if two fields are bound from the same variable, which is usually a copy-paste
+// mistake. Variables are compared after prefixes are applied, so nested fields with distinct prefixes do not conflict.
+func WithDetectDuplicates(detect bool) Option {
+	return func(b *binder) {
+		b.detectDuplicates = detect
+	}
+}
+
+// WithEmptyAsUnset treats a variable that is set to an empty value exactly as if it were unset, the semantics of
+// os.Getenv. By default an empty variable is considered set: it clears fields tagged with `env-allow-empty` and counts
+// when discovering the elements of slices and maps of structs, although other fields still fall back to their default.
+// Fields tagged with `env-allow-empty` opt out, so an empty variable still clears them.
+func WithEmptyAsUnset(emptyAsUnset bool) Option {
+	return func(b *binder) {
+		b.emptyAsUnset = emptyAsUnset
+	}
+}
+
+// WithFieldObserver registers a callback that is invoked for every field with a variable after it is bound, describing
+// the variable, where the value came from and whether it could be parsed. This is useful for metrics, such as counting
+// the fields that fell back to their defaults, and for audit logs. Fields that are not bound, such as those outside the
+// group selected with WithGroup, are not reported.
+func WithFieldObserver(fn func(FieldInfo)) Option {
+	return func(b *binder) {
+		b.observer = fn
+	}
+}
+
+// WithLookup reads variables with fn instead of from the environment of the process, such as a resolver backed by a
+// secrets manager. Options stack regardless of their order: every key passed to fn already has the prefix from
+// WithPrefix and any nested prefixes applied. Because fn cannot list its variables, the elements of slices and maps of
+// structs are not discovered and WithRejectUnknown reports nothing.
+func WithLookup(fn func(key string) (string, bool)) Option {
+	return func(b *binder) {
+		b.lookup = fn
+		b.environ = func() []string { return nil }
+		b.unlisted = true
+	}
+}
+
+// WithUnquote strips a matching pair of single or double quotes surrounding every value, and each element of slices and
+// maps, before it is parsed, so NAME='value' binds to value. Values with unbalanced quotes are left untouched. A field
+// can opt out with `env-unquote:"false"`, or opt in on its own with `env-unquote:"true"`.
+func WithUnquote(unquote bool) Option {
+	return func(b *binder) {
+		b.unquote = unquote
+	}
+}
+
+// WithExtendedBools parses every bool field as if it were tagged with `env-bool-mode:"extended"`, accepting yes/no,
+// on/off, y/n and enable(d)/disable(d) in any case as well as the tokens of strconv.ParseBool. A field can opt out with
+// `env-bool-mode:"standard"`.
+func WithExtendedBools(extended bool) Option {
+	return func(b *binder) {
+		b.extendedBools = extended
+	}
+}
+
+// WithUnderscoreDigits accepts underscores between the digits of integer and float values, as in Go literals, so
+// MAX_SIZE=1_000_000 binds to 1000000. It applies as if every field were tagged with `env-digit-separators:"_"`; a field
+// with its own `env-digit-separators` tag keeps it. Parsing is strict by default.
+func WithUnderscoreDigits(underscoreDigits bool) Option {
+	return func(b *binder) {
+		b.underscoreDigits = underscoreDigits
+	}
+}
+
+// WithStrictNumericFormat rejects integer values such as "3" for float fields and float values such as "3.5" for
+// integer fields, instead of converting or reporting a parse error. Only fields tagged with `env-strict-numeric:"true"`
+// are checked, and the tag has no effect without the option. A float value must contain a decimal point or an exponent,
+// or be inf, infinity or nan; an integer value must not contain a decimal point. Durations, fields with an `env-unit`
+// tag and the elements of slices and maps are not checked.
+func WithStrictNumericFormat(strict bool) Option {
+	return func(b *binder) {
+		b.strictNumeric = strict
+	}
+}
+
+// WithEnvTag reads the names of variables from the tag named tag instead of ENV_TAG, such as `config:"PORT"`. Unlike
+// assigning ENV_TAG, the option only affects the bind it is passed to, so structs with different tag names can be bound
+// concurrently. Fields without the tag are not bound. The combined options of the tag, such as required and default=,
+// work as they do in the `env` tag.
+func WithEnvTag(tag string) Option {
+	return func(b *binder) {
+		b.envTag = tag
+	}
+}
+
+// WithDefaultTag reads the defaults of fields from the tag named tag instead of ENV_DEFAULT_TAG, such as
+// `default:"8080"`. Like WithEnvTag, it only affects the bind it is passed to.
+func WithDefaultTag(tag string) Option {
+	return func(b *binder) {
+		b.defaultTag = tag
+	}
+}
+
+// WithClearOnUnset resets a field to its zero value when its variable is unset and it has no default, so that unsetting
+// a variable takes effect on the next refresh of BindEnvWithAutoRefresh. By default such a field keeps the value it had
+// before the bind, whether it was set by a previous bind or by the caller. Slices and maps of structs without any
+// elements are reset to nil, as are embedded pointers to structs without any variables set.
+func WithClearOnUnset(clear bool) Option {
+	return func(b *binder) {
+		b.clearOnUnset = clear
+	}
+}
+
+// WithKeyTransformer applies fn to the name of every variable before it is looked up, after prefixes are applied, such as
+// strings.ToUpper for a platform that uppercases names. It covers every name a field may be read from, including the
+// fallback names of the `env` tag and `env-default-from`. The elements of slices and maps of structs are discovered by
+// applying fn to their prefix, such as UPSTREAM_0_, so fn should map prefixes to prefixes. The default is the identity.
+func WithKeyTransformer(fn func(key string) string) Option {
+	return func(b *binder) {
+		b.keyTransformer = fn
+	}
+}
+
+// WithAliases reads a variable from its alias when it is unset or empty, mapping canonical names to legacy names such as
+// {"APP_DATABASE_URL": "APP_DB_URL"}, so a rename can be migrated without changing struct tags. Names are matched after
+// prefixes are applied and before the key transformer, which applies to both names. Each name of a field's fallback
+// chain is resolved with its alias before the next name is tried, so `env:"URL,DSN"` reads URL, the alias of URL, DSN
+// and then the alias of DSN. The canonical variable takes precedence over its alias, and an alias set to an empty value
+// is only used like any other empty variable. Aliases are not used to discover the elements of slices and maps of
+// structs.
+func WithAliases(aliases map[string]string) Option {
+	return func(b *binder) {
+		b.aliases = aliases
+	}
+}
+
+// withLookupMap looks up variables in the provided map instead of the environment of the process
+func withLookupMap(m map[string]string) Option {
+	return func(b *binder) {
+		b.lookup = func(key string) (string, bool) {
+			value, ok := m[key]
+			return value, ok
+		}
+		b.environ = func() []string {
+			environ := make([]string, 0, len(m))
+			for k, v := range m {
+				environ = append(environ, k+"="+v)
+			}
+			return environ
+		}
+	}
+}
+
+// binder binds struct fields from a source of variables
+type binder struct {
+	// lookup returns the value of a variable and whether it is set
+	lookup func(key string) (string, bool)
+	// environ returns every variable in the form KEY=VALUE
+	environ func() []string
+	// unlisted reports that the variables come from WithLookup, so environ cannot list them
+	unlisted bool
+	// envTag is the name of the tag holding the names of variables
+	envTag string
+	// defaultTag is the name of the tag holding the defaults of fields
+	defaultTag string
+	// requireAll treats every tagged field without a default as required
+	requireAll bool
+	// onRefresh is called after every auto-refresh
+	onRefresh func()
+	// onError receives errors that do not stop the bind
+	onError func(error)
+	// lenient falls back to the default when a value cannot be parsed
+	lenient bool
+	// prefix is prepended to the name of every variable
+	prefix string
+	// nestedSeparator joins the names of nested fields when names are flattened, or is empty when they are not
+	nestedSeparator string
+	// nameCase formats the names derived from field names
+	nameCase NameCase
+	// errorMode selects whether binding stops at the first error or collects them
+	errorMode ErrorMode
+	// onlyIfZero skips fields that do not hold their zero value
+	onlyIfZero bool
+	// requirePrefixPresent fails the bind when no variable starts with prefix
+	requirePrefixPresent bool
+	// rejectUnknownPrefix is the prefix of variables that must be read by a field, or empty to allow unknown variables
+	rejectUnknownPrefix string
+	// skippedKeys are the variables of fields skipped by onlyIfZero, which WithRejectUnknown does not report as unknown.
+	// Keys ending in "_" cover every variable with that prefix, e.g. the elements of a struct slice.
+	skippedKeys []string
+	// strict fails on tagged fields with unsupported types
+	strict bool
+	// clamp replaces out of range values with the nearest bound
+	clamp bool
+	// group is the group of fields to bind, or empty to bind every field
+	group string
+	// includeUngrouped binds fields without a group when binding a group
+	includeUngrouped bool
+	// inGroup is set while binding the fields of a nested struct that belongs to the group
+	inGroup bool
+	// defaultsFile is the path of a dotenv file of defaults, or empty when there is none
+	defaultsFile string
+	// fileDefaults holds the values read from defaultsFile by the current bind
+	fileDefaults map[string]string
+	// detectDuplicates fails the bind when two fields are bound from the same variable
+	detectDuplicates bool
+	// emptyAsUnset treats variables set to an empty value as unset
+	emptyAsUnset bool
+	// allowEmpty is set while resolving a field tagged with `env-allow-empty`, which sees empty variables as set even
+	// with emptyAsUnset
+	allowEmpty bool
+	// observer is called after each field is bound
+	observer func(FieldInfo)
+	// path is the path of the struct being bound, tracked when there is an observer
+	path string
+	// unquote strips surrounding quotes from every value
+	unquote bool
+	// extendedBools parses bools with the extended tokens
+	extendedBools bool
+	// underscoreDigits strips underscores from numeric values
+	underscoreDigits bool
+	// strictNumeric rejects integer values for float fields and float values for integer fields that opt in by tag
+	strictNumeric bool
+	// aliases maps canonical names of variables to the legacy names that are read when they are unset
+	aliases map[string]string
+	// keyTransformer maps the name of a variable to the name that is looked up, or is nil to look names up as they are
+	keyTransformer func(key string) string
+	// clearOnUnset resets fields whose variable is unset to their zero value
+	clearOnUnset bool
+	// maxRefreshErrors is the number of consecutive refresh failures that stops a refresh loop, or 0 to never stop
+	maxRefreshErrors int
+	// silentRefresh discards refresh failures that would otherwise be printed
+	silentRefresh bool
+	// lastSeen holds the raw value each field was bound from by the previous bind of a refresh loop, keyed by path, or is
+	// nil when changes are not tracked
+	lastSeen map[string]string
+	// refreshErrorInterval is the number of refresh cycles between reports of a repeated failure
+	refreshErrorInterval int
+}
+
+// newBinder returns a binder that reads from the environment of the process, configured with the provided options
+func newBinder(opts ...Option) *binder {
+	b := &binder{
+		lookup:               os.LookupEnv,
+		environ:              os.Environ,
+		envTag:               ENV_TAG,
+		defaultTag:           ENV_DEFAULT_TAG,
+		refreshErrorInterval: DEFAULT_REFRESH_ERROR_INTERVAL,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// transformKey returns the name that is looked up for the variable key
+func (b *binder) transformKey(key string) string {
+	if b.keyTransformer == nil {
+		return key
+	}
+	return b.keyTransformer(key)
+}
+
+// handleError passes an error that does not stop the bind to the error handler, if one is registered
+func (b *binder) handleError(err error) {
+	if b.onError != nil {
+		b.onError(err)
+	}
+}
@@ -0,0 +1,1028 @@
+package ectoenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBindEnvWithRequireAll(t *testing.T) {
+	type Config struct {
+		Host  string `env:"TEST_REQUIRE_ALL_HOST"`
+		Port  int    `env:"TEST_REQUIRE_ALL_PORT"`
+		Debug bool   `env:"TEST_REQUIRE_ALL_DEBUG" env-default:"false"`
+		Name  string
+	}
+
+	var config Config
+	if err := BindEnvWith(&config); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+
+	err := BindEnvWith(&config, WithRequireAll(true))
+	expected := "missing required environment variables: TEST_REQUIRE_ALL_HOST, TEST_REQUIRE_ALL_PORT"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("BindEnvWith() error = %v, want %v", err, expected)
+	}
+
+	os.Setenv("TEST_REQUIRE_ALL_HOST", "localhost")
+	os.Setenv("TEST_REQUIRE_ALL_PORT", "8080")
+	defer os.Unsetenv("TEST_REQUIRE_ALL_HOST")
+	defer os.Unsetenv("TEST_REQUIRE_ALL_PORT")
+
+	if err := BindEnvWith(&config, WithRequireAll(true)); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+	if config.Host != "localhost" || config.Port != 8080 {
+		t.Errorf("BindEnvWith() got = %v", config)
+	}
+}
+
+func TestWithOnRefresh(t *testing.T) {
+	type Config struct {
+		Value int `env:"TEST_ON_REFRESH"`
+	}
+
+	os.Setenv("TEST_ON_REFRESH", "not_an_int")
+	defer os.Unsetenv("TEST_ON_REFRESH")
+
+	// the refresh loop stops after its first failure, so that its goroutine does not outlive the test
+	refreshed := make(chan struct{}, 1)
+	b := newBinder(WithMaxRefreshErrors(1), WithSilentRefresh(true), WithOnRefresh(func() {
+		select {
+		case refreshed <- struct{}{}:
+		default:
+		}
+	}))
+
+	var config Config
+	b.refresh(1, reflect.ValueOf(&config).Elem())
+
+	select {
+	case <-refreshed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("WithOnRefresh() callback was not invoked after a failed refresh")
+	}
+}
+
+func TestBindEnvWithLenientParsing(t *testing.T) {
+	type Config struct {
+		Port    int  `env:"TEST_LENIENT_PORT" env-default:"8080"`
+		Retries int  `env:"TEST_LENIENT_RETRIES"`
+		Debug   bool `env:"TEST_LENIENT_DEBUG"`
+	}
+
+	envVars := map[string]string{
+		"TEST_LENIENT_PORT":    "eighty",
+		"TEST_LENIENT_RETRIES": "many",
+		"TEST_LENIENT_DEBUG":   "true",
+	}
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	var config Config
+	if err := BindEnvWith(&config); err == nil {
+		t.Fatalf("BindEnvWith() expected error without lenient parsing, got nil")
+	}
+
+	config = Config{}
+	var warnings []error
+	err := BindEnvWith(&config, WithLenientParsing(true), WithErrorHandler(func(err error) {
+		warnings = append(warnings, err)
+	}))
+	if err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+
+	expected := Config{Port: 8080, Retries: 0, Debug: true}
+	if config != expected {
+		t.Errorf("BindEnvWith() got = %v, want %v", config, expected)
+	}
+	if len(warnings) != 2 {
+		t.Errorf("BindEnvWith() reported %d warnings, want 2: %v", len(warnings), warnings)
+	}
+}
+
+func TestBindEnvWithRejectUnknown(t *testing.T) {
+	type Upstream struct {
+		URL string `env:"URL"`
+	}
+	type Config struct {
+		Port      int        `env:"APP_PORT,APP_LEGACY_PORT"`
+		Host      string     `env:"APP_HOST" env-default:"localhost"`
+		Upstreams []Upstream `env:"APP_UPSTREAM"`
+	}
+
+	m := map[string]string{
+		"APP_PORT":           "8080",
+		"APP_PROT":           "8081",
+		"APP_UPSTREAM_0_URL": "http://a",
+		"APP_UPSTREAM_0_URI": "http://b",
+		"OTHER_VAR":          "ignored",
+	}
+
+	var config Config
+	err := BindEnvWith(&config, WithRejectUnknown("APP_"), withLookupMap(m))
+	expected := "unknown environment variables with prefix APP_: APP_PROT, APP_UPSTREAM_0_URI"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("BindEnvWith() error = %v, want %v", err, expected)
+	}
+
+	delete(m, "APP_PROT")
+	delete(m, "APP_UPSTREAM_0_URI")
+	if err := BindEnvWith(&config, WithRejectUnknown("APP_"), withLookupMap(m)); err != nil {
+		t.Errorf("BindEnvWith() error = %v", err)
+	}
+}
+
+func TestBindEnvWithRejectUnknownOnlyIfZero(t *testing.T) {
+	type Upstream struct {
+		URL string `env:"URL"`
+	}
+	type Config struct {
+		Port      int        `env:"APP_PORT"`
+		Upstreams []Upstream `env:"APP_UPSTREAM"`
+	}
+
+	m := map[string]string{
+		"APP_PORT":           "8080",
+		"APP_UPSTREAM_0_URL": "http://a",
+		"APP_PROT":           "8081",
+	}
+
+	config := Config{Port: 9090, Upstreams: []Upstream{{URL: "http://b"}}}
+	err := BindEnvWith(&config, WithRejectUnknown("APP_"), WithOnlyIfZero(true), withLookupMap(m))
+	expected := "unknown environment variables with prefix APP_: APP_PROT"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("BindEnvWith() error = %v, want %v", err, expected)
+	}
+	if config.Port != 9090 {
+		t.Errorf("BindEnvWith() got Port = %d, want 9090", config.Port)
+	}
+}
+
+func TestWithRefreshErrorInterval(t *testing.T) {
+	var reported []string
+	b := newBinder(WithRefreshErrorInterval(3), WithErrorHandler(func(err error) {
+		reported = append(reported, err.Error())
+	}))
+
+	errA := errors.New("resolver unavailable")
+	errB := errors.New("invalid value")
+	results := []error{errA, errA, errA, errA, errB, nil, errA}
+
+	var reporter refreshReporter
+	for _, err := range results {
+		b.reportRefresh(&reporter, err)
+	}
+
+	expected := []string{"resolver unavailable", "resolver unavailable", "invalid value", "resolver unavailable"}
+	if !reflect.DeepEqual(reported, expected) {
+		t.Errorf("reportRefresh() reported %v, want %v", reported, expected)
+	}
+}
+
+func TestWithMaxRefreshErrors(t *testing.T) {
+	var reported []error
+	b := newBinder(WithMaxRefreshErrors(3), WithErrorHandler(func(err error) {
+		reported = append(reported, err)
+	}))
+
+	errA := errors.New("resolver unavailable")
+	var reporter refreshReporter
+	results := []error{errA, errA, nil, errA, errA}
+	for _, err := range results {
+		if b.reportRefresh(&reporter, err) {
+			t.Fatalf("reportRefresh() stopped before %d consecutive failures", 3)
+		}
+	}
+	if !b.reportRefresh(&reporter, errA) {
+		t.Fatalf("reportRefresh() did not stop after 3 consecutive failures")
+	}
+
+	last := reported[len(reported)-1]
+	if !errors.Is(last, ErrRefreshStopped) || !errors.Is(last, errA) {
+		t.Errorf("reportRefresh() reported %v, want it to wrap ErrRefreshStopped and the last failure", last)
+	}
+	if expected := "refresh stopped after 3 consecutive failures: resolver unavailable"; last.Error() != expected {
+		t.Errorf("reportRefresh() reported %q, want %q", last, expected)
+	}
+}
+
+func TestBindEnvWatchStopsAfterMaxRefreshErrors(t *testing.T) {
+	type Config struct {
+		Port int `env:"PORT"`
+	}
+
+	var mu sync.Mutex
+	port := "8080"
+	b := newBinder(WithMaxRefreshErrors(2), WithSilentRefresh(true), WithLookup(func(key string) (string, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		return port, true
+	}))
+
+	var config Config
+	rv := reflect.ValueOf(&config).Elem()
+	if err := b.bind(rv); err != nil {
+		t.Fatalf("bind() error = %v", err)
+	}
+
+	mu.Lock()
+	port = "http"
+	mu.Unlock()
+
+	changes := make(chan []string)
+	go b.watch(context.Background(), time.Millisecond, rv, changes)
+	select {
+	case _, ok := <-changes:
+		if ok {
+			t.Errorf("BindEnvWatch() sent a change, want the channel to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("BindEnvWatch() did not stop after repeated failures")
+	}
+}
+
+func TestWithSilentRefresh(t *testing.T) {
+	capture := func(b *binder) string {
+		t.Helper()
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("os.Pipe() error = %v", err)
+		}
+		stdout := os.Stdout
+		os.Stdout = w
+		defer func() { os.Stdout = stdout }()
+
+		var reporter refreshReporter
+		b.reportRefresh(&reporter, errors.New("resolver unavailable"))
+		b.reportRefresh(&reporter, nil)
+
+		w.Close()
+		out, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("io.ReadAll() error = %v", err)
+		}
+		return string(out)
+	}
+
+	if out := capture(newBinder()); out == "" {
+		t.Errorf("reportRefresh() printed nothing, want the failure and the recovery")
+	}
+	if out := capture(newBinder(WithSilentRefresh(true))); out != "" {
+		t.Errorf("reportRefresh() printed %q with WithSilentRefresh", out)
+	}
+
+	var reported []error
+	b := newBinder(WithSilentRefresh(true), WithErrorHandler(func(err error) {
+		reported = append(reported, err)
+	}))
+	if out := capture(b); out != "" || len(reported) != 1 {
+		t.Errorf("reportRefresh() printed %q and reported %v, want only the handler to be called", out, reported)
+	}
+}
+
+func TestBindEnvWithEmptyAsUnset(t *testing.T) {
+	type Upstream struct {
+		URL string `env:"URL"`
+	}
+	type Config struct {
+		Level     string     `env:"LEVEL" env-default:"info" env-allow-empty:"true"`
+		Upstreams []Upstream `env:"UPSTREAM"`
+	}
+
+	envVars := map[string]string{
+		"LEVEL":          "",
+		"UPSTREAM_0_URL": "",
+	}
+
+	var config Config
+	if err := BindEnvWith(&config, withLookupMap(envVars)); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+	expected := Config{Level: "", Upstreams: []Upstream{{}}}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvWith() got = %v, want %v", config, expected)
+	}
+
+	config = Config{}
+	if err := BindEnvWith(&config, withLookupMap(envVars), WithEmptyAsUnset(true)); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+	expected = Config{Level: ""}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvWith() got = %v, want %v", config, expected)
+	}
+
+	type Defaults struct {
+		Level string `env:"LEVEL" env-default:"info"`
+	}
+	var defaults Defaults
+	if err := BindEnvWith(&defaults, withLookupMap(envVars), WithEmptyAsUnset(true)); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+	if defaults.Level != "info" {
+		t.Errorf("BindEnvWith() got Level = %q, want info", defaults.Level)
+	}
+}
+
+func TestBindEnvWithLookup(t *testing.T) {
+	type Database struct {
+		URL string `env:"URL"`
+	}
+	type Config struct {
+		Port     int      `env:"PORT"`
+		Level    string   `env:"LEVEL" env-default:"info"`
+		Database Database `env:"DATABASE"`
+	}
+
+	values := map[string]string{
+		"APP_PORT":          "9090",
+		"APP_DATABASE__URL": "postgres://db",
+		"PORT":              "1",
+		"DATABASE__URL":     "postgres://other",
+	}
+
+	for _, lookupFirst := range []bool{true, false} {
+		t.Run(fmt.Sprintf("lookup first %v", lookupFirst), func(t *testing.T) {
+			var keys []string
+			lookup := WithLookup(func(key string) (string, bool) {
+				keys = append(keys, key)
+				value, ok := values[key]
+				return value, ok
+			})
+
+			opts := []Option{WithPrefix("APP_"), WithFlattenedNames("")}
+			if lookupFirst {
+				opts = append([]Option{lookup}, opts...)
+			} else {
+				opts = append(opts, lookup)
+			}
+
+			var config Config
+			if err := BindEnvWith(&config, opts...); err != nil {
+				t.Fatalf("BindEnvWith() error = %v", err)
+			}
+
+			expected := Config{Port: 9090, Level: "info", Database: Database{URL: "postgres://db"}}
+			if config != expected {
+				t.Errorf("BindEnvWith() got = %v, want %v", config, expected)
+			}
+
+			// every key has the prefix applied before it reaches the lookup
+			wantKeys := []string{"APP_PORT", "APP_LEVEL", "APP_DATABASE__URL"}
+			if !reflect.DeepEqual(keys, wantKeys) {
+				t.Errorf("BindEnvWith() looked up %v, want %v", keys, wantKeys)
+			}
+		})
+	}
+}
+
+func TestBindEnvInto(t *testing.T) {
+	type Config struct {
+		Port int    `env:"PORT" env-default:"8080"`
+		Host string `env:"HOST"`
+	}
+
+	config, err := BindEnvInto[Config](WithPrefix("APP_"), withLookupMap(map[string]string{"APP_HOST": "localhost"}))
+	if err != nil {
+		t.Fatalf("BindEnvInto() error = %v", err)
+	}
+	expected := Config{Port: 8080, Host: "localhost"}
+	if config != expected {
+		t.Errorf("BindEnvInto() got = %v, want %v", config, expected)
+	}
+
+	config, err = BindEnvInto[Config](withLookupMap(map[string]string{"PORT": "http"}))
+	if err == nil {
+		t.Errorf("BindEnvInto() expected error, got nil")
+	}
+	if config != (Config{}) {
+		t.Errorf("BindEnvInto() got = %v on error, want the zero value", config)
+	}
+
+	if _, err := BindEnvInto[int](); err == nil {
+		t.Errorf("BindEnvInto() expected error for a non-struct type, got nil")
+	}
+}
+
+func TestBindEnvWithUnquote(t *testing.T) {
+	type Config struct {
+		Name    string            `env:"NAME"`
+		Port    int               `env:"PORT"`
+		Tags    []string          `env:"TAGS"`
+		Hosts   []string          `env:"HOSTS"`
+		Labels  map[string]string `env:"LABELS"`
+		Broken  string            `env:"BROKEN"`
+		Literal string            `env:"LITERAL" env-unquote:"false"`
+	}
+
+	envVars := map[string]string{
+		"NAME":    "'value'",
+		"PORT":    `"8080"`,
+		"TAGS":    `'a','b',"c"`,
+		"HOSTS":   "'x,y'",
+		"LABELS":  `env="prod",'team'=core`,
+		"BROKEN":  `"value'`,
+		"LITERAL": "'kept'",
+	}
+
+	var config Config
+	if err := BindEnvWith(&config, withLookupMap(envVars)); err == nil {
+		t.Fatalf("BindEnvWith() expected error for a quoted int without WithUnquote, got nil")
+	}
+
+	config = Config{}
+	if err := BindEnvWith(&config, withLookupMap(envVars), WithUnquote(true)); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+
+	expected := Config{
+		Name:    "value",
+		Port:    8080,
+		Tags:    []string{"a", "b", "c"},
+		Hosts:   []string{"x", "y"},
+		Labels:  map[string]string{"env": "prod", "team": "core"},
+		Broken:  `"value'`,
+		Literal: "'kept'",
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvWith() got = %v, want %v", config, expected)
+	}
+}
+
+func TestBindEnvWithExtendedBools(t *testing.T) {
+	type Config struct {
+		Enabled bool `env:"ENABLED"`
+		Strict  bool `env:"STRICT" env-bool-mode:"standard"`
+	}
+
+	var config Config
+	err := BindEnvWith(&config, withLookupMap(map[string]string{"ENABLED": "enabled"}), WithExtendedBools(true))
+	if err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+	if !config.Enabled {
+		t.Errorf("BindEnvWith() got Enabled = false, want true")
+	}
+
+	err = BindEnvWith(&config, withLookupMap(map[string]string{"STRICT": "yes"}), WithExtendedBools(true))
+	if err == nil {
+		t.Errorf("BindEnvWith() expected error for a field that opts out, got nil")
+	}
+
+	err = BindEnvWith(&config, withLookupMap(map[string]string{"ENABLED": "yes"}))
+	if err == nil {
+		t.Errorf("BindEnvWith() expected error without extended bools, got nil")
+	}
+}
+
+func TestBindEnvWithUnderscoreDigits(t *testing.T) {
+	type Config struct {
+		MaxSize int     `env:"MAX_SIZE" env-max:"10_000_000"`
+		Limit   uint64  `env:"LIMIT"`
+		Rate    float64 `env:"RATE"`
+		Ports   []int   `env:"PORTS"`
+		Budget  int     `env:"BUDGET" env-digit-separators:","`
+	}
+
+	envVars := map[string]string{
+		"MAX_SIZE": "1_000_000",
+		"LIMIT":    "18_446_744",
+		"RATE":     "1_000.5",
+		"PORTS":    "8_080,9_090",
+		"BUDGET":   "1,000",
+	}
+
+	var config Config
+	if err := BindEnvWith(&config, withLookupMap(envVars)); err == nil {
+		t.Fatalf("BindEnvWith() expected error for underscores without WithUnderscoreDigits, got nil")
+	}
+
+	config = Config{}
+	if err := BindEnvWith(&config, withLookupMap(envVars), WithUnderscoreDigits(true)); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+
+	expected := Config{MaxSize: 1000000, Limit: 18446744, Rate: 1000.5, Ports: []int{8080, 9090}, Budget: 1000}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvWith() got = %v, want %v", config, expected)
+	}
+
+	// the field's own tag replaces the option, so underscores are not accepted for BUDGET
+	envVars["BUDGET"] = "1_000"
+	if err := BindEnvWith(&config, withLookupMap(envVars), WithUnderscoreDigits(true)); err == nil {
+		t.Errorf("BindEnvWith() expected error for underscores in a field with its own separators, got nil")
+	}
+}
+
+func TestBindEnvWithEnvTag(t *testing.T) {
+	type Server struct {
+		Host string `config:"HOST" default:"localhost"`
+	}
+	type Config struct {
+		Port    int      `config:"PORT,required"`
+		Retries int      `config:"RETRIES" default:"3"`
+		Tags    []string `config:"TAGS,separator=;"`
+		Ignored string   `env:"IGNORED"`
+		Server  Server
+	}
+
+	envVars := map[string]string{"PORT": "8080", "TAGS": "a;b", "IGNORED": "set"}
+
+	var config Config
+	if err := BindEnvWith(&config, withLookupMap(envVars), WithEnvTag("config"), WithDefaultTag("default")); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+
+	expected := Config{Port: 8080, Retries: 3, Tags: []string{"a", "b"}, Server: Server{Host: "localhost"}}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvWith() got = %v, want %v", config, expected)
+	}
+
+	err := BindEnvWith(&config, withLookupMap(map[string]string{}), WithEnvTag("config"))
+	if err == nil || err.Error() != "missing required environment variables: PORT" {
+		t.Errorf("BindEnvWith() error = %v, want missing PORT", err)
+	}
+}
+
+func TestBindEnvWithDefaultTagIgnoresCanonicalDefault(t *testing.T) {
+	type Config struct {
+		Port    int `env:"PORT" env-default:"80"`
+		Retries int `env:"RETRIES" env-default:"1" default:"3"`
+		Timeout int `env:"TIMEOUT,default=30"`
+	}
+
+	var config Config
+	if err := BindEnvWith(&config, withLookupMap(map[string]string{}), WithDefaultTag("default")); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+
+	expected := Config{Retries: 3, Timeout: 30}
+	if config != expected {
+		t.Errorf("BindEnvWith() got = %+v, want %+v", config, expected)
+	}
+}
+
+func TestBindEnvWithEnvTagConcurrent(t *testing.T) {
+	type First struct {
+		Port int `first:"PORT" first-default:"1"`
+	}
+	type Second struct {
+		Port int `second:"PORT" second-default:"2"`
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 200)
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			var config First
+			err := BindEnvWith(&config, withLookupMap(map[string]string{}), WithEnvTag("first"), WithDefaultTag("first-default"))
+			if err == nil && config.Port != 1 {
+				err = fmt.Errorf("first got Port = %d, want 1", config.Port)
+			}
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			var config Second
+			err := BindEnvWith(&config, withLookupMap(map[string]string{"PORT": "20"}), WithEnvTag("second"), WithDefaultTag("second-default"))
+			if err == nil && config.Port != 20 {
+				err = fmt.Errorf("second got Port = %d, want 20", config.Port)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("BindEnvWith() error = %v", err)
+		}
+	}
+}
+
+func TestBindEnvTagNamesTakeEffectOnNextBind(t *testing.T) {
+	type Config struct {
+		Port int `env:"PORT" config:"CONFIG_PORT"`
+	}
+
+	envVars := map[string]string{"PORT": "1", "CONFIG_PORT": "2"}
+
+	var config Config
+	if err := BindEnvFromMap(&config, envVars); err != nil || config.Port != 1 {
+		t.Fatalf("BindEnvFromMap() got Port = %d, error = %v, want 1", config.Port, err)
+	}
+
+	ENV_TAG = "config"
+	defer func() { ENV_TAG = "env" }()
+	if err := BindEnvFromMap(&config, envVars); err != nil || config.Port != 2 {
+		t.Errorf("BindEnvFromMap() after changing ENV_TAG got Port = %d, error = %v, want 2", config.Port, err)
+	}
+
+	if err := BindEnvWith(&config, withLookupMap(envVars), WithEnvTag("env")); err != nil || config.Port != 1 {
+		t.Errorf("BindEnvWith() got Port = %d, error = %v, want 1", config.Port, err)
+	}
+}
+
+func TestBindEnvWithClearOnUnset(t *testing.T) {
+	type Upstream struct {
+		URL string `env:"URL"`
+	}
+	type Config struct {
+		Host      string       `env:"HOST"`
+		Port      int          `env:"PORT" env-default:"8080"`
+		Tags      []string     `env:"TAGS"`
+		Counter   atomic.Int64 `env:"COUNTER"`
+		Upstreams []Upstream   `env:"UPSTREAM"`
+	}
+
+	envVars := map[string]string{"HOST": "localhost", "PORT": "9090", "TAGS": "a,b", "COUNTER": "3", "UPSTREAM_0_URL": "http://a"}
+	b := newBinder(withLookupMap(envVars), WithClearOnUnset(true))
+
+	var config Config
+	rv := reflect.ValueOf(&config).Elem()
+	if err := b.bind(rv); err != nil {
+		t.Fatalf("bind() error = %v", err)
+	}
+
+	for key := range envVars {
+		delete(envVars, key)
+	}
+	if err := b.bind(rv); err != nil {
+		t.Fatalf("bind() error = %v", err)
+	}
+	if config.Host != "" || config.Port != 8080 || config.Tags != nil || config.Counter.Load() != 0 || config.Upstreams != nil {
+		t.Errorf("bind() after unsetting got Host = %q, Port = %d, Tags = %v, Counter = %d, Upstreams = %v",
+			config.Host, config.Port, config.Tags, config.Counter.Load(), config.Upstreams)
+	}
+
+	// by default the fields keep their values
+	config.Host = "kept"
+	if err := BindEnvWith(&config, withLookupMap(envVars)); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+	if config.Host != "kept" {
+		t.Errorf("BindEnvWith() got Host = %q, want kept", config.Host)
+	}
+}
+
+func TestBindEnvWithKeyTransformer(t *testing.T) {
+	type Upstream struct {
+		URL string `env:"url"`
+	}
+	type Config struct {
+		Host      string     `env:"host"`
+		Port      int        `env:"port,legacy.port"`
+		Metrics   string     `env:"metrics.host" env-default-from:"host"`
+		Upstreams []Upstream `env:"upstream"`
+	}
+
+	envVars := map[string]string{
+		"APP_HOST":           "localhost",
+		"APP_LEGACY_PORT":    "8080",
+		"APP_UPSTREAM_0_URL": "http://a",
+		"APP_UNUSED":         "x",
+	}
+	transform := func(key string) string {
+		return "APP_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+	}
+
+	var config Config
+	if err := BindEnvWith(&config, withLookupMap(envVars), WithKeyTransformer(transform)); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+
+	expected := Config{Host: "localhost", Port: 8080, Metrics: "localhost", Upstreams: []Upstream{{URL: "http://a"}}}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvWith() got = %v, want %v", config, expected)
+	}
+
+	err := BindEnvWith(&config, withLookupMap(envVars), WithKeyTransformer(transform), WithRejectUnknown("APP_"))
+	if err == nil || err.Error() != "unknown environment variables with prefix APP_: APP_UNUSED" {
+		t.Errorf("BindEnvWith() error = %v, want APP_UNUSED to be reported", err)
+	}
+}
+
+func TestBindEnvWithAliases(t *testing.T) {
+	type Config struct {
+		URL     string `env:"DATABASE_URL,DSN"`
+		Port    int    `env:"PORT" env-required:"true"`
+		Host    string `env:"HOST" env-default:"localhost"`
+		Timeout string `env:"TIMEOUT"`
+	}
+
+	aliases := map[string]string{
+		"APP_DATABASE_URL": "APP_DB_URL",
+		"APP_PORT":         "APP_LISTEN_PORT",
+		"APP_HOST":         "APP_HOSTNAME",
+		"APP_TIMEOUT":      "APP_TIMEOUT_SECONDS",
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected Config
+		wantErr  string
+	}{
+		{
+			name:     "Aliases",
+			envVars:  map[string]string{"APP_DB_URL": "postgres://legacy", "APP_LISTEN_PORT": "8080"},
+			expected: Config{URL: "postgres://legacy", Port: 8080, Host: "localhost"},
+		},
+		{
+			name:     "Canonical takes precedence",
+			envVars:  map[string]string{"APP_DATABASE_URL": "postgres://new", "APP_DB_URL": "postgres://legacy", "APP_PORT": "9090", "APP_LISTEN_PORT": "8080"},
+			expected: Config{URL: "postgres://new", Port: 9090, Host: "localhost"},
+		},
+		{
+			name:     "Empty canonical",
+			envVars:  map[string]string{"APP_PORT": "", "APP_LISTEN_PORT": "8080", "APP_HOSTNAME": "example.com"},
+			expected: Config{Port: 8080, Host: "example.com"},
+		},
+		{
+			name:     "Alias before fallback",
+			envVars:  map[string]string{"APP_DB_URL": "postgres://legacy", "APP_DSN": "postgres://dsn", "APP_PORT": "1"},
+			expected: Config{URL: "postgres://legacy", Port: 1, Host: "localhost"},
+		},
+		{
+			name:     "Fallback",
+			envVars:  map[string]string{"APP_DSN": "postgres://dsn", "APP_PORT": "1"},
+			expected: Config{URL: "postgres://dsn", Port: 1, Host: "localhost"},
+		},
+		{
+			name:    "Missing required",
+			envVars: map[string]string{"PORT": "8080", "LISTEN_PORT": "8080"},
+			wantErr: "missing required environment variables: APP_PORT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvWith(&config, withLookupMap(tt.envVars), WithPrefix("APP_"), WithAliases(aliases))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvWith() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvWith() error = %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("BindEnvWith() got = %+v, want %+v", config, tt.expected)
+			}
+		})
+	}
+
+	var config Config
+	envVars := map[string]string{"APP_LISTEN_PORT": "8080", "APP_UNUSED": "x"}
+	err := BindEnvWith(&config, withLookupMap(envVars), WithPrefix("APP_"), WithAliases(aliases), WithRejectUnknown("APP_"))
+	if err == nil || err.Error() != "unknown environment variables with prefix APP_: APP_UNUSED" {
+		t.Errorf("BindEnvWith() error = %v, want only APP_UNUSED to be reported", err)
+	}
+}
+
+func TestBindEnvWithRequirePrefixPresent(t *testing.T) {
+	type Config struct {
+		Port int    `env:"PORT" env-default:"8080"`
+		Host string `env:"HOST" env-default:"localhost"`
+	}
+
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		opts    []Option
+		wantErr string
+	}{
+		{
+			name:    "Present",
+			envVars: map[string]string{"APP_HOST": "example.com"},
+		},
+		{
+			name:    "Unknown variable with prefix",
+			envVars: map[string]string{"APP_OTHER": "x"},
+		},
+		{
+			name:    "Missing",
+			envVars: map[string]string{"PORT": "9090", "OTHER_HOST": "example.com"},
+			wantErr: "no environment variables with prefix APP_ are set",
+		},
+		{
+			name:    "Empty with WithEmptyAsUnset",
+			envVars: map[string]string{"APP_HOST": ""},
+			opts:    []Option{WithEmptyAsUnset(true)},
+			wantErr: "no environment variables with prefix APP_ are set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			opts := append([]Option{withLookupMap(tt.envVars), WithPrefix("APP_"), WithRequirePrefixPresent(true)}, tt.opts...)
+			err := BindEnvWith(&config, opts...)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvWith() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("BindEnvWith() error = %v", err)
+			}
+		})
+	}
+
+	var config Config
+	if err := BindEnvWith(&config, withLookupMap(map[string]string{}), WithPrefix("APP_")); err != nil {
+		t.Errorf("BindEnvWith() error = %v, want the check to be opt-in", err)
+	}
+
+	lookup := func(key string) (string, bool) { return "example.com", key == "APP_HOST" }
+	err := BindEnvWith(&config, WithLookup(lookup), WithPrefix("APP_"), WithRequirePrefixPresent(true))
+	expected := "WithRequirePrefixPresent cannot be used with WithLookup, which cannot list its variables"
+	if err == nil || err.Error() != expected {
+		t.Errorf("BindEnvWith() error = %v, want %s", err, expected)
+	}
+}
+
+func TestBindEnvWithOnlyIfZero(t *testing.T) {
+	type Upstream struct {
+		URL string `env:"URL"`
+	}
+	type Database struct {
+		URL  string `env:"URL" env-required:"true"`
+		Pool int    `env:"POOL" env-default:"10"`
+	}
+	type Config struct {
+		Host      string     `env:"HOST" env-required:"true"`
+		Port      int        `env:"PORT" env-default:"8080"`
+		Debug     bool       `env:"DEBUG"`
+		Tags      []string   `env:"TAGS"`
+		Upstreams []Upstream `env:"UPSTREAM"`
+		Database  Database   `env-prefix:"DB_"`
+	}
+
+	envVars := map[string]string{
+		"HOST":           "env-host",
+		"PORT":           "9090",
+		"DEBUG":          "true",
+		"TAGS":           "a,b",
+		"UPSTREAM_0_URL": "http://env",
+		"DB_POOL":        "20",
+	}
+
+	config := Config{
+		Host:      "code-host",
+		Tags:      []string{"preset"},
+		Upstreams: []Upstream{{URL: "http://preset"}},
+		Database:  Database{URL: "postgres://preset"},
+	}
+	if err := BindEnvWith(&config, withLookupMap(envVars), WithOnlyIfZero(true)); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+
+	expected := Config{
+		Host:      "code-host",
+		Port:      9090,
+		Debug:     true,
+		Tags:      []string{"preset"},
+		Upstreams: []Upstream{{URL: "http://preset"}},
+		Database:  Database{URL: "postgres://preset", Pool: 20},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvWith() got = %+v, want %+v", config, expected)
+	}
+
+	// a zero required field still requires its variable
+	config = Config{Host: "code-host"}
+	err := BindEnvWith(&config, withLookupMap(map[string]string{}), WithOnlyIfZero(true))
+	if err == nil || err.Error() != "missing required environment variables: DB_URL" {
+		t.Errorf("BindEnvWith() error = %v, want DB_URL to be missing", err)
+	}
+}
+
+func TestBindEnvWithErrorMode(t *testing.T) {
+	type Config struct {
+		Port    int    `env:"PORT"`
+		Host    string `env:"HOST"`
+		Debug   bool   `env:"DEBUG"`
+		Retries int    `env:"RETRIES"`
+	}
+
+	env := map[string]string{"PORT": "eighty", "HOST": "localhost", "DEBUG": "maybe", "RETRIES": "3"}
+
+	tests := []struct {
+		name       string
+		mode       ErrorMode
+		expected   Config
+		wantFields []string
+	}{
+		{
+			name:       "fail fast",
+			mode:       FailFast,
+			expected:   Config{Port: 1, Host: "previous", Retries: 1},
+			wantFields: []string{"Port"},
+		},
+		{
+			name:       "collect",
+			mode:       Collect,
+			expected:   Config{Port: 1, Host: "localhost", Retries: 3},
+			wantFields: []string{"Port", "Debug"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{Port: 1, Host: "previous", Retries: 1}
+			err := BindEnvWith(&config, withLookupMap(env), WithErrorMode(tt.mode))
+			if err == nil {
+				t.Fatalf("BindEnvWith() expected error, got nil")
+			}
+
+			for _, name := range []string{"Port", "Debug"} {
+				want := slices.Contains(tt.wantFields, name)
+				if got := strings.Contains(err.Error(), "field "+name+"."); got != want {
+					t.Errorf("BindEnvWith() error = %v, reports %s = %v, want %v", err, name, got, want)
+				}
+			}
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) || parseErr.Name != "Port" {
+				t.Errorf("BindEnvWith() error = %v, want a *ParseError for Port", err)
+			}
+			if config != tt.expected {
+				t.Errorf("BindEnvWith() got = %+v, want %+v", config, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvMulti(t *testing.T) {
+	type Logging struct {
+		Level string `env:"LOG_LEVEL" env-default:"info"`
+	}
+	type Database struct {
+		URL  string `env:"DB_URL" env-required:"true"`
+		Pool int    `env:"DB_POOL"`
+	}
+	type HTTP struct {
+		Port int `env:"HTTP_PORT"`
+	}
+
+	env := map[string]string{"APP_LOG_LEVEL": "debug", "APP_DB_POOL": "ten", "APP_HTTP_PORT": "8080"}
+
+	var logging Logging
+	var database Database
+	var server HTTP
+	err := BindEnvMulti([]Option{withLookupMap(env), WithPrefix("APP_")}, &logging, &database, &server)
+	if err == nil {
+		t.Fatalf("BindEnvMulti() expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "unable to bind *ectoenv.Database: missing required environment variables: APP_DB_URL") {
+		t.Errorf("BindEnvMulti() error = %v, want the missing APP_DB_URL", err)
+	}
+
+	// the structs after the failing one are still bound
+	if logging.Level != "debug" || server.Port != 8080 {
+		t.Errorf("BindEnvMulti() got = %+v, %+v, want Level=debug Port=8080", logging, server)
+	}
+
+	env["APP_DB_URL"] = "postgres://db"
+	err = BindEnvMulti([]Option{withLookupMap(env), WithPrefix("APP_")}, &logging, &database, &server)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Name != "Pool" {
+		t.Errorf("BindEnvMulti() error = %v, want a *ParseError for Pool", err)
+	}
+
+	env["APP_DB_POOL"] = "10"
+	if err := BindEnvMulti([]Option{withLookupMap(env), WithPrefix("APP_")}, &logging, &database, &server); err != nil {
+		t.Fatalf("BindEnvMulti() error = %v", err)
+	}
+	if database != (Database{URL: "postgres://db", Pool: 10}) {
+		t.Errorf("BindEnvMulti() got = %+v", database)
+	}
+
+	if err := BindEnvMulti(nil, logging); err == nil {
+		t.Errorf("BindEnvMulti() expected error for a non-pointer, got nil")
+	}
+}
@@ -0,0 +1,49 @@
+package ectoenv
+
+import (
+	"reflect"
+	"sync"
+)
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = map[reflect.Type]func(string) (reflect.Value, error){}
+)
+
+// RegisterParser registers a function that parses values into fields of type T, taking precedence over the built-in
+// handling of the type. A struct type with a registered parser is bound from a single value rather than field by field,
+// which lets opaque types such as a custom Date wrapping time.Time be bound. Registering a parser for a type that
+// already has one replaces it.
+// fn: the function used to parse values of type T
+func RegisterParser[T any](fn func(string) (T, error)) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	parsers[reflect.TypeOf((*T)(nil)).Elem()] = func(s string) (reflect.Value, error) {
+		val, err := fn(s)
+		return reflect.ValueOf(&val).Elem(), err
+	}
+}
+
+// getParser returns the parser registered for t, if any
+func getParser(t reflect.Type) (func(string) (reflect.Value, error), bool) {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+	parser, ok := parsers[t]
+	return parser, ok
+}
+
+// hasParser reports whether a parser is registered for t
+func hasParser(t reflect.Type) bool {
+	_, ok := getParser(t)
+	return ok
+}
+
+// setParsedField sets the field with the parser registered for its type
+func setParsedField(field reflect.Value, name string, parser func(string) (reflect.Value, error), envValue string) error {
+	val, err := parser(envValue)
+	if err != nil {
+		return &ParseError{Name: name, Value: envValue, Kind: field.Type().String(), Err: err}
+	}
+	field.Set(val)
+	return nil
+}
@@ -0,0 +1,132 @@
+package ectoenv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testDate struct {
+	t time.Time
+}
+
+func TestRegisterParser(t *testing.T) {
+	RegisterParser(func(s string) (testDate, error) {
+		parsed, err := time.Parse("2006-01-02", s)
+		return testDate{t: parsed}, err
+	})
+
+	type Config struct {
+		Start    testDate   `env:"START"`
+		Holidays []testDate `env:"HOLIDAYS"`
+		End      *testDate  `env:"END"`
+	}
+
+	var config Config
+	err := BindEnvFromMap(&config, map[string]string{
+		"START":    "2024-01-02",
+		"HOLIDAYS": "2024-12-25,2024-12-26",
+		"END":      "2024-12-31",
+	})
+	if err != nil {
+		t.Fatalf("BindEnvFromMap() error = %v", err)
+	}
+
+	expected := Config{
+		Start: testDate{t: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		Holidays: []testDate{
+			{t: time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)},
+			{t: time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC)},
+		},
+		End: &testDate{t: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvFromMap() got = %v, want %v", config, expected)
+	}
+
+	err = BindEnvFromMap(&config, map[string]string{"START": "tomorrow"})
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Name != "Start" {
+		t.Errorf("BindEnvFromMap() error = %v, want ParseError for Start", err)
+	}
+}
+
+type testVersion struct {
+	major, minor int
+}
+
+type testPortRange []int
+
+func TestRegisterParserMap(t *testing.T) {
+	RegisterParser(func(s string) (testDate, error) {
+		parsed, err := time.Parse("2006-01-02", s)
+		return testDate{t: parsed}, err
+	})
+	RegisterParser(func(s string) (testVersion, error) {
+		var v testVersion
+		_, err := fmt.Sscanf(s, "v%d.%d", &v.major, &v.minor)
+		return v, err
+	})
+	RegisterParser(func(s string) (testPortRange, error) {
+		var first, last int
+		if _, err := fmt.Sscanf(s, "%d-%d", &first, &last); err != nil {
+			return nil, err
+		}
+		var ports testPortRange
+		for port := first; port <= last; port++ {
+			ports = append(ports, port)
+		}
+		return ports, nil
+	})
+
+	type Config struct {
+		Releases map[testVersion]testDate  `env:"RELEASES"`
+		Ports    map[string]testPortRange  `env:"PORTS"`
+		Pointers map[testVersion]*testDate `env:"POINTERS"`
+	}
+
+	var config Config
+	err := BindEnvFromMap(&config, map[string]string{
+		"RELEASES": "v1.0=2024-01-02,v1.1=2024-03-04",
+		"PORTS":    "web=8000-8002,admin=9000-9000",
+		"POINTERS": "v2.0=2024-12-31",
+	})
+	if err != nil {
+		t.Fatalf("BindEnvFromMap() error = %v", err)
+	}
+
+	expected := Config{
+		Releases: map[testVersion]testDate{
+			{1, 0}: {t: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+			{1, 1}: {t: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)},
+		},
+		Ports: map[string]testPortRange{"web": {8000, 8001, 8002}, "admin": {9000}},
+		Pointers: map[testVersion]*testDate{
+			{2, 0}: {t: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvFromMap() got = %v, want %v", config, expected)
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		kind string
+	}{
+		{name: "invalid key", env: map[string]string{"RELEASES": "one=2024-01-02"}, kind: "ectoenv.testVersion"},
+		{name: "invalid value", env: map[string]string{"RELEASES": "v1.0=tomorrow"}, kind: "ectoenv.testDate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, tt.env)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) || parseErr.Name != "Releases" || parseErr.Kind != tt.kind {
+				t.Errorf("BindEnvFromMap() error = %v, want ParseError for Releases of kind %s", err, tt.kind)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package ectoenv
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var certificateType = reflect.TypeOf(x509.Certificate{})
+
+var bytesType = reflect.TypeOf([]byte(nil))
+
+// decodePEM decodes the first PEM block of the value. Values injected through the environment often have their
+// newlines escaped, so a value without any newlines has its \n escapes decoded first.
+func decodePEM(envValue string) (*pem.Block, error) {
+	if !strings.Contains(envValue, "\n") {
+		envValue = strings.ReplaceAll(envValue, `\n`, "\n")
+	}
+
+	block, _ := pem.Decode([]byte(envValue))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	return block, nil
+}
+
+// setPEMBytesField decodes a PEM block into a []byte field, storing the DER encoded contents of the block
+func setPEMBytesField(field reflect.Value, name string, envValue string) error {
+	block, err := decodePEM(envValue)
+	if err != nil {
+		return &ParseError{Name: name, Value: envValue, Kind: "pem", Err: err}
+	}
+	field.SetBytes(block.Bytes)
+	return nil
+}
+
+// setCertificateField decodes a PEM encoded certificate into an x509.Certificate field
+func setCertificateField(field reflect.Value, name string, envValue string) error {
+	block, err := decodePEM(envValue)
+	if err != nil {
+		return &ParseError{Name: name, Value: envValue, Kind: "certificate", Err: err}
+	}
+	if block.Type != "CERTIFICATE" {
+		return &ParseError{Name: name, Value: envValue, Kind: "certificate", Err: fmt.Errorf("unexpected PEM block type %s, expected CERTIFICATE", block.Type)}
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return &ParseError{Name: name, Value: envValue, Kind: "certificate", Err: err}
+	}
+	field.Set(reflect.ValueOf(*cert))
+	return nil
+}
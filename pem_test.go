@@ -0,0 +1,67 @@
+package ectoenv
+
+import (
+	"bytes"
+	"crypto/x509"
+	"strings"
+	"testing"
+)
+
+const testCertificatePEM = `-----BEGIN CERTIFICATE-----
+MIIBHDCBw6ADAgECAgEBMAoGCCqGSM49BAMCMBcxFTATBgNVBAMTDGVjdG9lbnYt
+dGVzdDAgFw0yNDAxMDEwMDAwMDBaGA8yMTI0MDEwMTAwMDAwMFowFzEVMBMGA1UE
+AxMMZWN0b2Vudi10ZXN0MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEt7Hf+EDq
+WdAfXE8jFBSyqXclLnYmDsnXTLZPl7TUWNl2V101EVPvoxJ07EZZJptCj80xj2Et
+RIq60nKrzhRYXTAKBggqhkjOPQQDAgNIADBFAiEAr9Jvo6mteUwBjdjXsK9hZVO1
+pdYtCXNq1HHqmfoUr/wCIDYsck7/D78ctC/bSmy58B9iHiUc/5dPdnfx+LDK3P6t
+-----END CERTIFICATE-----
+`
+
+func TestBindEnvPEM(t *testing.T) {
+	type Config struct {
+		DER     []byte            `env:"TLS_DER" env-format:"pem"`
+		Cert    *x509.Certificate `env:"TLS_CERT"`
+		Escaped x509.Certificate  `env:"TLS_ESCAPED"`
+	}
+
+	var config Config
+	err := BindEnvFromMap(&config, map[string]string{
+		"TLS_DER":     testCertificatePEM,
+		"TLS_CERT":    testCertificatePEM,
+		"TLS_ESCAPED": strings.ReplaceAll(testCertificatePEM, "\n", `\n`),
+	})
+	if err != nil {
+		t.Fatalf("BindEnvFromMap() error = %v", err)
+	}
+
+	if config.Cert == nil || config.Cert.Subject.CommonName != "ectoenv-test" {
+		t.Fatalf("Cert got = %v, want certificate for ectoenv-test", config.Cert)
+	}
+	if config.Escaped.Subject.CommonName != "ectoenv-test" {
+		t.Errorf("Escaped got = %v, want certificate for ectoenv-test", config.Escaped.Subject)
+	}
+	if !bytes.Equal(config.DER, config.Cert.Raw) {
+		t.Errorf("DER does not match the raw certificate")
+	}
+}
+
+func TestBindEnvMalformedPEM(t *testing.T) {
+	type Config struct {
+		Cert *x509.Certificate `env:"TLS_CERT"`
+	}
+
+	tests := map[string]string{
+		"No block":     "not a certificate",
+		"Wrong type":   strings.ReplaceAll(testCertificatePEM, "CERTIFICATE", "PRIVATE KEY"),
+		"Invalid body": "-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n",
+	}
+
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			var config Config
+			if err := BindEnvFromMap(&config, map[string]string{"TLS_CERT": value}); err == nil {
+				t.Errorf("BindEnvFromMap() expected error, got nil")
+			}
+		})
+	}
+}
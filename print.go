@@ -0,0 +1,79 @@
+package ectoenv
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// PrintEnv binds the provided struct as BindEnvWith does and writes a table of the fields it binds to w, giving the path
+// of each field, the variable it is read from, its effective value and the source of the value, such as "env" or
+// "default". Secrets are redacted and values containing line breaks or tabs are quoted, so the output suits a
+// `config show` command:
+//
+//	FIELD         KEY         VALUE  SOURCE
+//	Port          APP_PORT    8080   default
+//	Database.URL  APP_DB_URL  ****   env
+//
+// v: a non-nil pointer to a struct
+// w: the writer the table is written to
+// opts: the options to apply
+// returns: an error if the bind fails or the table cannot be written
+func PrintEnv(v interface{}, w io.Writer, opts ...Option) error {
+	rv, err := validateInput(v)
+	if err != nil {
+		return err
+	}
+
+	var fields []FieldInfo
+	b := newBinder(opts...)
+	observer := b.observer
+	b.observer = func(info FieldInfo) {
+		fields = append(fields, info)
+		if observer != nil {
+			observer(info)
+		}
+	}
+	if err := b.bind(rv); err != nil {
+		return err
+	}
+
+	entries := b.collectEnvEntries(rv, "", "", true)
+	values := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		values[entry.name] = entry.value
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tKEY\tVALUE\tSOURCE")
+	for _, info := range fields {
+		value, ok := values[info.Path]
+		if !ok {
+			// the elements of an indexed slice are serialized one per variable
+			value = indexedEntryValues(entries, info.Path)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.Path, info.Key, printableValue(value), info.Source)
+	}
+	return tw.Flush()
+}
+
+// indexedEntryValues joins the values of the entries named path[0], path[1] and so on with commas
+func indexedEntryValues(entries []envEntry, path string) string {
+	var values []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.name, path+"[") && !strings.Contains(entry.name[len(path):], ".") {
+			values = append(values, entry.value)
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+// printableValue quotes a value that would break the table, such as a PEM block spanning several lines
+func printableValue(value string) string {
+	if strings.ContainsAny(value, "\t\r\n") {
+		return strconv.Quote(value)
+	}
+	return value
+}
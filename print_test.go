@@ -0,0 +1,86 @@
+package ectoenv
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrintEnv(t *testing.T) {
+	type Database struct {
+		URL      string `env:"URL" env-required:"true"`
+		Password string `env:"PASSWORD" env-secret:"true"`
+	}
+	type Config struct {
+		Port     int      `env:"PORT" env-default:"8080"`
+		Hosts    []string `env:"HOSTS"`
+		Shards   []int    `env:"SHARD" env-indexed:"true"`
+		Cert     string   `env:"CERT"`
+		Database Database `env-prefix:"DB_"`
+		Timeout  string   `env:"TIMEOUT"`
+	}
+
+	envVars := map[string]string{
+		"APP_HOSTS":       "a,b",
+		"APP_SHARD_0":     "1",
+		"APP_SHARD_1":     "2",
+		"APP_CERT":        "line1\nline2",
+		"APP_DB_URL":      "postgres://db",
+		"APP_DB_PASSWORD": "hunter2",
+	}
+
+	var buf bytes.Buffer
+	var config Config
+	if err := PrintEnv(&config, &buf, withLookupMap(envVars), WithPrefix("APP_")); err != nil {
+		t.Fatalf("PrintEnv() error = %v", err)
+	}
+
+	expected := `FIELD              KEY              VALUE           SOURCE
+Port               APP_PORT         8080            default
+Hosts              APP_HOSTS        a,b             env
+Shards             APP_SHARD_0      1,2             env
+Cert               APP_CERT         "line1\nline2"  env
+Database.URL       APP_DB_URL       postgres://db   env
+Database.Password  APP_DB_PASSWORD  ****            env
+Timeout            APP_TIMEOUT                      unset
+`
+	if buf.String() != expected {
+		t.Errorf("PrintEnv() got =\n%s\nwant =\n%s", buf.String(), expected)
+	}
+	if config.Port != 8080 {
+		t.Errorf("PrintEnv() did not bind the struct, got = %+v", config)
+	}
+
+	buf.Reset()
+	if err := PrintEnv(&config, &buf, withLookupMap(map[string]string{})); err == nil {
+		t.Errorf("PrintEnv() expected an error for a missing required variable")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("PrintEnv() wrote %q after a failed bind", buf.String())
+	}
+}
+
+func TestPrintEnvFlattenedNames(t *testing.T) {
+	type Server struct {
+		Port int
+	}
+	type Config struct {
+		Name   string
+		Server Server
+	}
+
+	envVars := map[string]string{"NAME": "app", "SERVER__PORT": "8080"}
+
+	var buf bytes.Buffer
+	var config Config
+	if err := PrintEnv(&config, &buf, withLookupMap(envVars), WithFlattenedNames("")); err != nil {
+		t.Fatalf("PrintEnv() error = %v", err)
+	}
+
+	expected := `FIELD        KEY           VALUE  SOURCE
+Name         NAME          app    env
+Server.Port  SERVER__PORT  8080   env
+`
+	if buf.String() != expected {
+		t.Errorf("PrintEnv() got =\n%s\nwant =\n%s", buf.String(), expected)
+	}
+}
@@ -0,0 +1,27 @@
+package ectoenv
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// ENV_VALIDATE_JSON_TAG is the tag used to store the value of a json.RawMessage field as it is, without checking that it
+// is well-formed JSON, when set to "false"
+var ENV_VALIDATE_JSON_TAG = "env-validate-json"
+
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// setRawMessageField stores the bytes of the value in a json.RawMessage field without decoding them, so that the JSON
+// can be forwarded or decoded later. The value must be well-formed JSON unless the field is tagged with
+// `env-validate-json:"false"`.
+func setRawMessageField(field reflect.Value, structField reflect.StructField, envValue string) error {
+	if getTag(structField, ENV_VALIDATE_JSON_TAG) != "false" {
+		var raw json.RawMessage
+		if err := json.Unmarshal([]byte(envValue), &raw); err != nil {
+			return &ParseError{Name: structField.Name, Value: envValue, Kind: "json", Err: err}
+		}
+	}
+
+	field.SetBytes([]byte(envValue))
+	return nil
+}
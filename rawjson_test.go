@@ -0,0 +1,77 @@
+package ectoenv
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestBindEnvRawMessage(t *testing.T) {
+	type Config struct {
+		Payload  json.RawMessage  `env:"PAYLOAD"`
+		Forward  json.RawMessage  `env:"FORWARD" env-validate-json:"false"`
+		Optional *json.RawMessage `env:"OPTIONAL"`
+	}
+
+	optional := json.RawMessage(`[1, 2]`)
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected Config
+		wantErr  string
+	}{
+		{
+			name:     "Raw bytes",
+			envVars:  map[string]string{"PAYLOAD": `{"a": [1, 2], "b": null}`, "OPTIONAL": `[1, 2]`},
+			expected: Config{Payload: json.RawMessage(`{"a": [1, 2], "b": null}`), Optional: &optional},
+		},
+		{
+			name:     "Scalar",
+			envVars:  map[string]string{"PAYLOAD": `"text"`},
+			expected: Config{Payload: json.RawMessage(`"text"`)},
+		},
+		{
+			name:     "Not validated",
+			envVars:  map[string]string{"FORWARD": `{"a": `},
+			expected: Config{Forward: json.RawMessage(`{"a": `)},
+		},
+		{
+			name:    "Invalid",
+			envVars: map[string]string{"PAYLOAD": `{"a": `},
+			wantErr: `unable to set value for field Payload. failed to parse {"a":  as json: unexpected end of JSON input`,
+		},
+		{
+			name:    "Trailing data",
+			envVars: map[string]string{"PAYLOAD": `{} {}`},
+			wantErr: `unable to set value for field Payload. failed to parse {} {} as json: invalid character '{' after top-level value`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, tt.envVars)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvFromMap() error = %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("BindEnvFromMap() got = %+v, want %+v", config, tt.expected)
+			}
+		})
+	}
+
+	config := Config{Payload: json.RawMessage(`{"a":1}`)}
+	data, err := MarshalEnv(config)
+	if err != nil {
+		t.Fatalf("MarshalEnv() error = %v", err)
+	}
+	if expected := "PAYLOAD=\"{\\\"a\\\":1}\"\nFORWARD=\nOPTIONAL=\n"; string(data) != expected {
+		t.Errorf("MarshalEnv() got = %q, want %q", data, expected)
+	}
+}
@@ -0,0 +1,50 @@
+package ectoenv
+
+import "reflect"
+
+// trackChanges makes the binds of a refresh loop record the raw value each field is bound from, so that later refreshes
+// skip the fields whose value is unchanged
+func (b *binder) trackChanges() {
+	b.lastSeen = map[string]string{}
+}
+
+// rebind binds rv during a refresh. When changes are tracked, only the fields whose variable changed since the previous
+// bind are parsed and set again. A failed bind may leave fields that do not hold the values that were recorded, such as
+// the discarded copy of BindEnvReloadable, so the next refresh binds every field.
+func (b *binder) rebind(rv reflect.Value) error {
+	err := b.bind(rv)
+	if err != nil && b.lastSeen != nil {
+		b.lastSeen = map[string]string{}
+	}
+	return err
+}
+
+// fieldUnchanged reports whether the field was bound from the same raw value by the previous bind. Fields with an
+// `env-setter` tag are never skipped, since their setter may depend on other fields.
+func (b *binder) fieldUnchanged(field reflect.StructField, seen string) bool {
+	if b.lastSeen == nil || getTag(field, ENV_SETTER_TAG) != "" {
+		return false
+	}
+	last, ok := b.lastSeen[b.path+field.Name]
+	return ok && last == seen
+}
+
+// recordField records the raw value the field was bound from, when changes are tracked
+func (b *binder) recordField(field reflect.StructField, seen string) {
+	if b.lastSeen != nil {
+		b.lastSeen[b.path+field.Name] = seen
+	}
+}
+
+// forgetField removes the recorded value of the field, so that the next bind sets it whatever its value
+func (b *binder) forgetField(field reflect.StructField) {
+	delete(b.lastSeen, b.path+field.Name)
+}
+
+// untracked stops tracking changes while the fields of a newly allocated struct are bound, since a value recorded for
+// the struct it replaces does not mean the new one is set, returning a function that resumes tracking
+func (b *binder) untracked() func() {
+	lastSeen := b.lastSeen
+	b.lastSeen = nil
+	return func() { b.lastSeen = lastSeen }
+}
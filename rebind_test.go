@@ -0,0 +1,110 @@
+package ectoenv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// countedValue counts the number of times a value of its type is set from the environment
+type countedValue struct {
+	value string
+}
+
+var countedSets int
+
+func (c *countedValue) SetFromEnv(value string) error {
+	countedSets++
+	if value == "invalid" {
+		return errInvalidCounted
+	}
+	c.value = value
+	return nil
+}
+
+var errInvalidCounted = errors.New("invalid value")
+
+func TestRebindOnlyChangedFields(t *testing.T) {
+	type Item struct {
+		Name countedValue `env:"NAME"`
+	}
+	type Nested struct {
+		Host countedValue `env:"HOST"`
+	}
+	type Config struct {
+		Host   countedValue `env:"HOST"`
+		Port   countedValue `env:"PORT" env-default:"8080"`
+		Nested Nested       `env-prefix:"NESTED_"`
+		Items  []Item       `env:"ITEM"`
+	}
+
+	envVars := map[string]string{"HOST": "a", "NESTED_HOST": "b", "ITEM_0_NAME": "c"}
+	b := newBinder(withLookupMap(envVars), WithClearOnUnset(true))
+	b.trackChanges()
+
+	var config Config
+	rv := reflect.ValueOf(&config).Elem()
+	rebind := func(wantSets int) {
+		t.Helper()
+		countedSets = 0
+		if err := b.rebind(rv); err != nil {
+			t.Fatalf("rebind() error = %v", err)
+		}
+		if countedSets != wantSets {
+			t.Errorf("rebind() set %d values, want %d", countedSets, wantSets)
+		}
+	}
+
+	rebind(4)
+	// only the elements of the slice of structs, which are allocated on every bind, are set again
+	rebind(1)
+
+	envVars["HOST"] = "x"
+	rebind(2)
+	if config.Host.value != "x" || config.Nested.Host.value != "b" {
+		t.Errorf("rebind() got = %+v", config)
+	}
+
+	// clearing an unset field is recorded, so setting the variable back to its earlier value sets the field again
+	delete(envVars, "NESTED_HOST")
+	rebind(1)
+	if config.Nested.Host.value != "" {
+		t.Errorf("rebind() got = %+v, want Nested.Host to be cleared", config)
+	}
+	envVars["NESTED_HOST"] = "b"
+	rebind(2)
+	if config.Nested.Host.value != "b" {
+		t.Errorf("rebind() got = %+v, want Nested.Host to be set again", config)
+	}
+
+	// a failed bind makes the next one set every field
+	envVars["PORT"] = "invalid"
+	countedSets = 0
+	if err := b.rebind(rv); err == nil {
+		t.Fatalf("rebind() expected an error")
+	}
+	envVars["PORT"] = "9090"
+	rebind(4)
+	if config.Port.value != "9090" {
+		t.Errorf("rebind() got = %+v", config)
+	}
+}
+
+func TestRebindWithoutTracking(t *testing.T) {
+	type Config struct {
+		Host countedValue `env:"HOST"`
+	}
+
+	b := newBinder(withLookupMap(map[string]string{"HOST": "a"}))
+	var config Config
+	rv := reflect.ValueOf(&config).Elem()
+	countedSets = 0
+	for i := 0; i < 3; i++ {
+		if err := b.rebind(rv); err != nil {
+			t.Fatalf("rebind() error = %v", err)
+		}
+	}
+	if countedSets != 3 {
+		t.Errorf("rebind() set %d values, want 3", countedSets)
+	}
+}
@@ -0,0 +1,72 @@
+package ectoenv
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// BindEnvReloadable binds a new value of type T and rebinds it every interval until ctx is canceled. The returned getter
+// is safe for concurrent use and returns the latest snapshot: each refresh binds a deep copy of the current value and
+// swaps it in atomically once the bind succeeds, so a getter never observes a partially bound value and the values it
+// returned earlier are never modified. A failed refresh keeps the previous snapshot and is reported as it is by
+// BindEnvWithAutoRefresh. As with BindEnvWithAutoRefresh, a field whose variable is unset keeps its previous value
+// unless WithClearOnUnset is used.
+// ctx: the context that stops the refreshes when canceled
+// interval: the time between refreshes
+// opts: the options to apply to the initial bind and every refresh
+// returns: the getter, or an error if T is not a struct or if the initial bind fails
+func BindEnvReloadable[T any](ctx context.Context, interval time.Duration, opts ...Option) (func() T, error) {
+	initial := new(T)
+	rv, err := validateInput(initial)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newBinder(opts...)
+	b.trackChanges()
+	if err := b.bind(rv); err != nil {
+		return nil, err
+	}
+
+	var current atomic.Pointer[T]
+	current.Store(initial)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var reporter refreshReporter
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if ctx.Err() != nil {
+				// both were ready and select picked the tick
+				return
+			}
+
+			// bind a copy so that the snapshots handed out are never modified
+			next := new(T)
+			rv := reflect.ValueOf(next).Elem()
+			rv.Set(deepCopy(reflect.ValueOf(current.Load()).Elem()))
+			err := b.rebind(rv)
+			if err == nil {
+				current.Store(next)
+			}
+			stop := b.reportRefresh(&reporter, err)
+			if b.onRefresh != nil {
+				b.onRefresh()
+			}
+			if stop {
+				return
+			}
+		}
+	}()
+
+	return func() T {
+		return *current.Load()
+	}, nil
+}
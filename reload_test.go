@@ -0,0 +1,112 @@
+package ectoenv
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBindEnvReloadable(t *testing.T) {
+	type Config struct {
+		Host  string   `env:"HOST"`
+		Port  int      `env:"PORT"`
+		Hosts []string `env:"HOSTS"`
+	}
+
+	var mu sync.Mutex
+	envVars := map[string]string{"HOST": "localhost", "PORT": "8080", "HOSTS": "a,b"}
+	setEnv := func(key, value string) {
+		mu.Lock()
+		defer mu.Unlock()
+		envVars[key] = value
+	}
+	lookup := WithLookup(func(key string) (string, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		value, ok := envVars[key]
+		return value, ok
+	})
+
+	var errMu sync.Mutex
+	var refreshErr error
+	onError := WithErrorHandler(func(err error) {
+		errMu.Lock()
+		defer errMu.Unlock()
+		refreshErr = err
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	get, err := BindEnvReloadable[Config](ctx, 5*time.Millisecond, lookup, onError)
+	if err != nil {
+		t.Fatalf("BindEnvReloadable() error = %v", err)
+	}
+
+	first := get()
+	if first.Host != "localhost" || first.Port != 8080 {
+		t.Fatalf("BindEnvReloadable() got = %+v", first)
+	}
+
+	waitFor := func(cond func(Config) bool) Config {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if config := get(); cond(config) {
+				return config
+			}
+			time.Sleep(time.Millisecond)
+		}
+		t.Fatalf("BindEnvReloadable() did not pick up the change, got = %+v", get())
+		return Config{}
+	}
+
+	setEnv("PORT", "9090")
+	setEnv("HOSTS", "c")
+	waitFor(func(c Config) bool { return c.Port == 9090 && len(c.Hosts) == 1 && c.Hosts[0] == "c" })
+	if first.Port != 8080 || first.Hosts[0] != "a" {
+		t.Errorf("BindEnvReloadable() modified an earlier snapshot, got = %+v", first)
+	}
+
+	// a failed refresh keeps the previous snapshot
+	setEnv("PORT", "http")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		errMu.Lock()
+		failed := refreshErr != nil
+		errMu.Unlock()
+		if failed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("BindEnvReloadable() did not report the failed refresh")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if config := get(); config.Port != 9090 {
+		t.Errorf("BindEnvReloadable() got = %+v after a failed refresh, want the previous snapshot", config)
+	}
+
+	// no refreshes happen after the context is canceled
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	setEnv("PORT", "1")
+	time.Sleep(20 * time.Millisecond)
+	if config := get(); config.Port != 9090 {
+		t.Errorf("BindEnvReloadable() got = %+v after the context was canceled", config)
+	}
+}
+
+func TestBindEnvReloadableErrors(t *testing.T) {
+	if _, err := BindEnvReloadable[int](context.Background(), time.Second); err == nil {
+		t.Errorf("BindEnvReloadable() expected an error for a non-struct type")
+	}
+
+	type Config struct {
+		Port int `env:"PORT"`
+	}
+	_, err := BindEnvReloadable[Config](context.Background(), time.Second, withLookupMap(map[string]string{"PORT": "http"}))
+	if err == nil {
+		t.Errorf("BindEnvReloadable() expected an error for an invalid initial value")
+	}
+}
@@ -0,0 +1,44 @@
+package ectoenv
+
+import "reflect"
+
+// SelfBinder is implemented by types that bind themselves, letting a package own the binding of its configuration while
+// a parent struct orchestrates. When a field's type, or a pointer to it, implements SelfBinder, BindSelf is called in
+// place of the default binding. lookup returns the value of a variable and whether it is set; any prefix of the parent,
+// such as one from WithPrefix, is applied to the key before it is looked up.
+type SelfBinder interface {
+	BindSelf(lookup func(key string) (string, bool)) error
+}
+
+var selfBinderType = reflect.TypeOf((*SelfBinder)(nil)).Elem()
+
+// isSelfBinder reports whether t, or a pointer to t, implements SelfBinder
+func isSelfBinder(t reflect.Type) bool {
+	return t.Implements(selfBinderType) || reflect.PointerTo(t).Implements(selfBinderType)
+}
+
+// asSelfBinder returns the SelfBinder for the field, allocating a nil pointer field if needed
+func asSelfBinder(field reflect.Value) (SelfBinder, bool) {
+	if !isSelfBinder(field.Type()) {
+		return nil, false
+	}
+
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		return field.Interface().(SelfBinder), true
+	}
+
+	if field.CanAddr() && reflect.PointerTo(field.Type()).Implements(selfBinderType) {
+		return field.Addr().Interface().(SelfBinder), true
+	}
+	return field.Interface().(SelfBinder), true
+}
+
+// bindSelf delegates binding to the SelfBinder, applying prefix to every key it looks up
+func (b *binder) bindSelf(selfBinder SelfBinder, prefix string) error {
+	return selfBinder.BindSelf(func(key string) (string, bool) {
+		return b.lookup(prefix + key)
+	})
+}
@@ -0,0 +1,54 @@
+package ectoenv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testSelfBinder struct {
+	Endpoints []string
+}
+
+func (c *testSelfBinder) BindSelf(lookup func(key string) (string, bool)) error {
+	value, ok := lookup("ENDPOINTS")
+	if !ok {
+		return errors.New("ENDPOINTS is not set")
+	}
+	c.Endpoints = strings.Split(value, " ")
+	return nil
+}
+
+func TestBindEnvSelfBinder(t *testing.T) {
+	type Config struct {
+		Port    int `env:"PORT"`
+		Plugin  testSelfBinder
+		Pointer *testSelfBinder
+	}
+
+	m := map[string]string{
+		"APP_PORT":      "8080",
+		"APP_ENDPOINTS": "a b",
+	}
+
+	var config Config
+	if err := BindEnvWith(&config, WithPrefix("APP_"), withLookupMap(m)); err != nil {
+		t.Fatalf("BindEnvWith() error = %v", err)
+	}
+
+	if config.Port != 8080 {
+		t.Errorf("Port got = %v, want %v", config.Port, 8080)
+	}
+	if strings.Join(config.Plugin.Endpoints, ",") != "a,b" {
+		t.Errorf("Plugin got = %v, want [a b]", config.Plugin.Endpoints)
+	}
+	if config.Pointer == nil || strings.Join(config.Pointer.Endpoints, ",") != "a,b" {
+		t.Errorf("Pointer got = %v, want [a b]", config.Pointer)
+	}
+
+	delete(m, "APP_ENDPOINTS")
+	err := BindEnvWith(&config, WithPrefix("APP_"), withLookupMap(m))
+	if err == nil || !strings.Contains(err.Error(), "field Plugin") {
+		t.Errorf("BindEnvWith() error = %v, want error naming field Plugin", err)
+	}
+}
@@ -0,0 +1,71 @@
+package ectoenv
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ENV_SETTER_TAG is the tag used to name a method of the struct containing a field, with the signature
+// func(string) error, that is called with the value instead of assigning the field, e.g. `env-setter:"SetTimeout"`
+var ENV_SETTER_TAG = "env-setter"
+
+// EnvSetter is implemented by types that enforce their invariants when they are assigned from the environment. A field
+// whose pointer implements EnvSetter is set by calling SetFromEnv with the value instead of being parsed.
+type EnvSetter interface {
+	SetFromEnv(value string) error
+}
+
+var envSetterType = reflect.TypeOf((*EnvSetter)(nil)).Elem()
+
+// isEnvSetter reports whether a pointer to t implements EnvSetter
+func isEnvSetter(t reflect.Type) bool {
+	return t.Kind() != reflect.Ptr && reflect.PointerTo(t).Implements(envSetterType)
+}
+
+// setEnvSetterField sets a field whose pointer implements EnvSetter by calling SetFromEnv on a new zero value that is
+// then stored in the field, so that the field is left unchanged if SetFromEnv fails
+func setEnvSetterField(field reflect.Value, name string, envValue string) error {
+	val := reflect.New(field.Type())
+	if err := val.Interface().(EnvSetter).SetFromEnv(envValue); err != nil {
+		return fmt.Errorf("unable to set value for field %s. SetFromEnv failed: %w", name, err)
+	}
+	field.Set(val.Elem())
+	return nil
+}
+
+// assignValue binds the value to the field of rv, calling the method named by the field's `env-setter` tag instead when
+// it has one. The value passed to the method is unquoted, transformed and checked against `env-oneof` like any other,
+// but the method is responsible for every other validation; an empty value is passed as it is.
+func (b *binder) assignValue(rv reflect.Value, field reflect.Value, structField reflect.StructField, envValue string) error {
+	name := getTag(structField, ENV_SETTER_TAG)
+	if name == "" {
+		return b.bindValue(field, structField, envValue)
+	}
+
+	var method reflect.Value
+	if rv.CanAddr() {
+		method = rv.Addr().MethodByName(name)
+	}
+	if !method.IsValid() {
+		return fmt.Errorf("unable to set value for field %s. invalid %s tag %s: no such method on *%s", structField.Name, ENV_SETTER_TAG, name, rv.Type())
+	}
+	setter, ok := method.Interface().(func(string) error)
+	if !ok {
+		return fmt.Errorf("unable to set value for field %s. invalid %s tag %s: %s is not func(string) error", structField.Name, ENV_SETTER_TAG, name, method.Type())
+	}
+
+	if envValue != "" {
+		var err error
+		if envValue, err = b.prepareValue(structField, b.parseOptions(structField), envValue); err != nil {
+			return err
+		}
+		if envValue, err = validateOneOf(structField, envValue); err != nil {
+			return err
+		}
+	}
+
+	if err := setter(envValue); err != nil {
+		return fmt.Errorf("unable to set value for field %s. %s failed: %w", structField.Name, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package ectoenv
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// port is a port number that rejects privileged ports when it is assigned
+type port struct {
+	number int
+}
+
+func (p *port) SetFromEnv(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if n < 1024 || n > 65535 {
+		return fmt.Errorf("port %d is out of range", n)
+	}
+	p.number = n
+	return nil
+}
+
+var errTimeoutTooLong = errors.New("timeout must be shorter than the deadline")
+
+type setterConfig struct {
+	Deadline time.Duration `env:"DEADLINE" env-default:"1m"`
+	Timeout  time.Duration `env:"TIMEOUT" env-setter:"SetTimeout" env-transform:"lower"`
+	Port     port          `env:"PORT"`
+	Missing  string        `env:"MISSING" env-setter:"SetMissing"`
+	Wrong    string        `env:"WRONG" env-setter:"SetWrong"`
+}
+
+func (c *setterConfig) SetTimeout(value string) error {
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	if timeout >= c.Deadline {
+		return errTimeoutTooLong
+	}
+	c.Timeout = timeout
+	return nil
+}
+
+func (c *setterConfig) SetWrong(value int) {}
+
+func TestBindEnvSetter(t *testing.T) {
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected setterConfig
+		wantErr  string
+	}{
+		{
+			name:     "Setters",
+			envVars:  map[string]string{"TIMEOUT": "30S", "PORT": "8080"},
+			expected: setterConfig{Deadline: time.Minute, Timeout: 30 * time.Second, Port: port{number: 8080}},
+		},
+		{
+			name:    "Setter method error",
+			envVars: map[string]string{"TIMEOUT": "2m"},
+			wantErr: "unable to set value for field Timeout. SetTimeout failed: timeout must be shorter than the deadline",
+		},
+		{
+			name:    "SetFromEnv error",
+			envVars: map[string]string{"PORT": "80"},
+			wantErr: "unable to set value for field Port. SetFromEnv failed: port 80 is out of range",
+		},
+		{
+			name:    "Missing method",
+			envVars: map[string]string{"MISSING": "x"},
+			wantErr: "unable to set value for field Missing. invalid env-setter tag SetMissing: no such method on *ectoenv.setterConfig",
+		},
+		{
+			name:    "Wrong signature",
+			envVars: map[string]string{"WRONG": "x"},
+			wantErr: "unable to set value for field Wrong. invalid env-setter tag SetWrong: func(int) is not func(string) error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config setterConfig
+			err := BindEnvFromMap(&config, tt.envVars)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvFromMap() error = %v", err)
+			}
+			if config != tt.expected {
+				t.Errorf("BindEnvFromMap() got = %+v, want %+v", config, tt.expected)
+			}
+		})
+	}
+
+	var config setterConfig
+	err := BindEnvFromMap(&config, map[string]string{"TIMEOUT": "2m"})
+	if !errors.Is(err, errTimeoutTooLong) {
+		t.Errorf("BindEnvFromMap() error = %v, want it to wrap the setter's error", err)
+	}
+}
+
+func TestBindEnvSetterNested(t *testing.T) {
+	type Config struct {
+		Server setterConfig `env-prefix:"SERVER_"`
+		Ports  []port       `env:"PORTS"`
+	}
+
+	var config Config
+	err := BindEnvFromMap(&config, map[string]string{"SERVER_TIMEOUT": "2m"})
+	expected := "unable to set value for field Server: unable to set value for field Timeout. SetTimeout failed: timeout must be shorter than the deadline"
+	if err == nil || err.Error() != expected {
+		t.Errorf("BindEnvFromMap() error = %v, want %s", err, expected)
+	}
+
+	if err := BindEnvFromMap(&config, map[string]string{"PORTS": "8080,9090"}); err != nil {
+		t.Fatalf("BindEnvFromMap() error = %v", err)
+	}
+	if len(config.Ports) != 2 || config.Ports[1].number != 9090 {
+		t.Errorf("BindEnvFromMap() got = %+v", config.Ports)
+	}
+}
@@ -0,0 +1,65 @@
+package ectoenv
+
+import "reflect"
+
+// Snapshot returns a deep copy of the provided struct that can be read without racing against BindEnvWithAutoRefresh.
+// The copy is taken while no refresh is in progress, so it always reflects a single, complete bind.
+// v: a non-nil pointer to a struct
+// returns: a pointer to a deep copy of the struct, with the same type as v, or an error if the provided value is not a
+// non-nil pointer to a struct
+func Snapshot(v interface{}) (interface{}, error) {
+	rv, err := validateInput(v)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshMu.RLock()
+	defer refreshMu.RUnlock()
+
+	cp := reflect.New(rv.Type())
+	cp.Elem().Set(deepCopy(rv))
+	return cp.Interface(), nil
+}
+
+func deepCopy(src reflect.Value) reflect.Value {
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return src
+		}
+		dst := reflect.New(src.Type().Elem())
+		dst.Elem().Set(deepCopy(src.Elem()))
+		return dst
+	case reflect.Slice:
+		if src.IsNil() {
+			return src
+		}
+		dst := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			dst.Index(i).Set(deepCopy(src.Index(i)))
+		}
+		return dst
+	case reflect.Map:
+		if src.IsNil() {
+			return src
+		}
+		dst := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(deepCopy(iter.Key()), deepCopy(iter.Value()))
+		}
+		return dst
+	case reflect.Struct:
+		// copy the whole struct first so unexported fields are preserved, then replace the exported fields with deep
+		// copies of their values
+		dst := reflect.New(src.Type()).Elem()
+		dst.Set(src)
+		for i := 0; i < dst.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				dst.Field(i).Set(deepCopy(src.Field(i)))
+			}
+		}
+		return dst
+	}
+	return src
+}
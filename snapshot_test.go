@@ -0,0 +1,62 @@
+package ectoenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	type Nested struct {
+		Tags []string
+	}
+	type Config struct {
+		Name   string
+		Ports  []int
+		Labels map[string]string
+		Nested Nested
+		Ptr    *Nested
+	}
+
+	config := Config{
+		Name:   "app",
+		Ports:  []int{80, 443},
+		Labels: map[string]string{"env": "prod"},
+		Nested: Nested{Tags: []string{"a"}},
+		Ptr:    &Nested{Tags: []string{"b"}},
+	}
+
+	snap, err := Snapshot(&config)
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+
+	cp, ok := snap.(*Config)
+	if !ok {
+		t.Fatalf("Snapshot() returned %T, want *Config", snap)
+	}
+	if !reflect.DeepEqual(*cp, config) {
+		t.Fatalf("Snapshot() got = %v, want %v", *cp, config)
+	}
+
+	config.Ports[0] = 8080
+	config.Labels["env"] = "dev"
+	config.Nested.Tags[0] = "changed"
+	config.Ptr.Tags[0] = "changed"
+
+	expected := Config{
+		Name:   "app",
+		Ports:  []int{80, 443},
+		Labels: map[string]string{"env": "prod"},
+		Nested: Nested{Tags: []string{"a"}},
+		Ptr:    &Nested{Tags: []string{"b"}},
+	}
+	if !reflect.DeepEqual(*cp, expected) {
+		t.Errorf("Snapshot() shares memory with the original, got = %v, want %v", *cp, expected)
+	}
+}
+
+func TestSnapshotInvalidInput(t *testing.T) {
+	if _, err := Snapshot(struct{}{}); err == nil {
+		t.Errorf("Snapshot() expected error for non-pointer input, got nil")
+	}
+}
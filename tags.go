@@ -0,0 +1,119 @@
+package ectoenv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// canonicalEnvTag and canonicalDefaultTag are the names under which binding reads the variable names and the default of
+// a field, whatever tag names the binder is configured with
+const (
+	canonicalEnvTag     = "env"
+	canonicalDefaultTag = "env-default"
+)
+
+// field returns the i-th field of rt with its tags resolved by resolveTags
+func (b *binder) field(rt reflect.Type, i int) reflect.StructField {
+	return b.resolveTags(rt.Field(i))
+}
+
+// resolveTags returns a copy of the field whose `env` and `env-default` tags hold the values of the tags named by the
+// binder's envTag and defaultTag, so that the tag names are resolved once per bind rather than read from the package
+// globals while binding. A field without the configured `env` tag is not bound, and a field without the configured
+// default tag has no default.
+func (b *binder) resolveTags(field reflect.StructField) reflect.StructField {
+	if b.envTag == canonicalEnvTag && b.defaultTag == canonicalDefaultTag {
+		return field
+	}
+
+	// StructTag.Lookup returns the first match, so the resolved tags take precedence over any canonical tags of the field
+	envTag := field.Tag.Get(b.envTag)
+	resolved := fmt.Sprintf("%s:%q", canonicalEnvTag, envTag)
+	if b.defaultTag != canonicalDefaultTag {
+		// The canonical default is always resolved so that a canonical `env-default` tag does not apply under a custom
+		// default tag. A field without the custom tag falls back to the default option of its `env` tag, if any.
+		value, ok := field.Tag.Lookup(b.defaultTag)
+		if !ok {
+			_, options := parseEnvTag(envTag)
+			value = options["default"]
+		}
+		resolved += fmt.Sprintf(" %s:%q", canonicalDefaultTag, value)
+	}
+	field.Tag = reflect.StructTag(resolved + " " + string(field.Tag))
+	return field
+}
+
+// getTag returns the value of the tag for the field. When the field has no such tag, the matching option of the
+// combined `env` tag is used instead, e.g. `env:"PORT,required,default=8080"` provides "true" for env-required and
+// "8080" for env-default. Separate tags take precedence over combined options.
+func getTag(field reflect.StructField, tag string) string {
+	if value, ok := field.Tag.Lookup(tag); ok {
+		return value
+	}
+
+	_, options := parseEnvTag(field.Tag.Get(canonicalEnvTag))
+	return options[strings.TrimPrefix(tag, "env-")]
+}
+
+// hasTag reports whether the field sets the tag, either separately or as an option of the combined `env` tag
+func hasTag(field reflect.StructField, tag string) bool {
+	if _, ok := field.Tag.Lookup(tag); ok {
+		return true
+	}
+
+	_, options := parseEnvTag(field.Tag.Get(canonicalEnvTag))
+	_, ok := options[strings.TrimPrefix(tag, "env-")]
+	return ok
+}
+
+// envTagNames returns the variable names listed in the field's `env` tag, or nil if the tag is absent or "-"
+func envTagNames(field reflect.StructField) []string {
+	envTag := field.Tag.Get(canonicalEnvTag)
+	if envTag == "-" {
+		return nil
+	}
+
+	names, _ := parseEnvTag(envTag)
+	return names
+}
+
+// parseEnvTag splits a combined `env` tag into variable names and options, similar to the comma options of
+// encoding/json. A token of the form key=value is an option named after a tag without its "env-" prefix, such as
+// default=8080 or separator=;. A bare token naming a boolean tag, such as required, sets that option to "true". Every
+// other token is a variable name. Option values cannot contain commas; use the separate tag for those.
+func parseEnvTag(envTag string) ([]string, map[string]string) {
+	var names []string
+	options := map[string]string{}
+	for _, token := range strings.Split(envTag, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+
+		if key, value, ok := strings.Cut(token, "="); ok {
+			options[key] = value
+			continue
+		}
+
+		if isFlagOption(token) {
+			options[token] = "true"
+			continue
+		}
+
+		names = append(names, token)
+	}
+	return names, options
+}
+
+// isFlagOption reports whether token names a boolean tag that can be set in the combined `env` tag without a value
+func isFlagOption(token string) bool {
+	flags := []string{ENV_REQUIRED_TAG, ENV_SECRET_TAG, ENV_QUOTED_TAG, ENV_ALLOW_EMPTY_TAG, ENV_ONEOF_FOLD_TAG, ENV_SKIP_EMPTY_TAG,
+		ENV_UNQUOTE_TAG, ENV_INDEXED_TAG, ENV_DEFAULT_TEMPLATE_TAG, ENV_STRICT_NUMERIC_TAG}
+	for _, tag := range flags {
+		if token == strings.TrimPrefix(tag, "env-") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package ectoenv
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseEnvTag(t *testing.T) {
+	names, options := parseEnvTag("PORT,LEGACY_PORT,required,separator=;,default=8080")
+
+	if !reflect.DeepEqual(names, []string{"PORT", "LEGACY_PORT"}) {
+		t.Errorf("parseEnvTag() names = %v, want [PORT LEGACY_PORT]", names)
+	}
+	expected := map[string]string{"required": "true", "separator": ";", "default": "8080"}
+	if !reflect.DeepEqual(options, expected) {
+		t.Errorf("parseEnvTag() options = %v, want %v", options, expected)
+	}
+}
+
+func TestBindEnvCombinedTag(t *testing.T) {
+	type Config struct {
+		Port     int      `env:"TEST_COMBINED_PORT,default=8080"`
+		Hosts    []string `env:"TEST_COMBINED_HOSTS,separator=;"`
+		Token    string   `env:"TEST_COMBINED_TOKEN,required"`
+		Override string   `env:"TEST_COMBINED_OVERRIDE,default=combined" env-default:"separate"`
+	}
+
+	var config Config
+	err := BindEnv(&config)
+	if err == nil || err.Error() != "missing required environment variables: TEST_COMBINED_TOKEN" {
+		t.Fatalf("BindEnv() error = %v, want missing TEST_COMBINED_TOKEN", err)
+	}
+
+	envVars := map[string]string{
+		"TEST_COMBINED_HOSTS": "a;b",
+		"TEST_COMBINED_TOKEN": "token",
+	}
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+
+	expected := Config{
+		Port:     8080,
+		Hosts:    []string{"a", "b"},
+		Token:    "token",
+		Override: "separate",
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnv() got = %v, want %v", config, expected)
+	}
+}
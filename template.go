@@ -0,0 +1,146 @@
+package ectoenv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// ENV_DEFAULT_TEMPLATE_TAG is the tag used to render the `env-default` of a field as a text/template against the struct
+// that contains it, once the other fields of the struct are bound, e.g. `env-default:"{{.Host}}:{{.Port}}"`
+var ENV_DEFAULT_TEMPLATE_TAG = "env-default-template"
+
+// templateDefault is a field whose default is a template, rendered once the other fields of its struct are bound
+type templateDefault struct {
+	// field is the value of the field
+	field reflect.Value
+	// structField describes the field
+	structField reflect.StructField
+	// key is the variable the field is bound from
+	key string
+}
+
+// isTemplateDefault reports whether the default of the field is rendered as a template
+func isTemplateDefault(field reflect.StructField) bool {
+	return getTag(field, ENV_DEFAULT_TEMPLATE_TAG) == "true"
+}
+
+// setTemplateDefaults renders the template defaults of the fields of rv, whose other fields are already bound, and binds
+// the results. A template may refer to another templated field, which is rendered first; templates that refer to each
+// other are an error. With Collect, every template that fails is reported and the others are still rendered.
+func (b *binder) setTemplateDefaults(rv reflect.Value, defaults []templateDefault) error {
+	var errs []error
+	var pending []templateDefault
+	templates := make(map[string]*template.Template, len(defaults))
+	for _, p := range defaults {
+		tmpl, err := template.New(p.structField.Name).Option("missingkey=error").Parse(getTag(p.structField, canonicalDefaultTag))
+		if err != nil {
+			err = fmt.Errorf("unable to set value for field %s. invalid default template: %w", p.structField.Name, err)
+			if err := b.fail(&errs, err); err != nil {
+				return err
+			}
+			continue
+		}
+		templates[p.structField.Name] = tmpl
+		pending = append(pending, p)
+	}
+
+	data := rv.Interface()
+	if rv.CanAddr() {
+		data = rv.Addr().Interface()
+	}
+
+	for len(pending) > 0 {
+		waiting := make(map[string]bool, len(pending))
+		for _, p := range pending {
+			waiting[p.structField.Name] = true
+		}
+
+		var remaining []templateDefault
+		for _, p := range pending {
+			tmpl := templates[p.structField.Name]
+			if refersTo(tmpl.Tree.Root, waiting) {
+				remaining = append(remaining, p)
+				continue
+			}
+
+			var rendered strings.Builder
+			err := tmpl.Execute(&rendered, data)
+			if err != nil {
+				err = fmt.Errorf("unable to set value for field %s. failed to render default template: %w", p.structField.Name, err)
+			} else {
+				err = b.assignValue(rv, p.field, p.structField, rendered.String())
+			}
+			b.observe(p.structField, p.key, SourceDefault, err)
+			if err != nil {
+				if !b.lenient {
+					if err := b.fail(&errs, err); err != nil {
+						return err
+					}
+					continue
+				}
+				b.handleError(err)
+			}
+		}
+
+		if len(remaining) == len(pending) {
+			names := make([]string, 0, len(remaining))
+			for _, p := range remaining {
+				names = append(names, p.structField.Name)
+			}
+			err := fmt.Errorf("unable to set value for fields %s. default templates refer to each other", strings.Join(names, ", "))
+			if err := b.fail(&errs, err); err != nil {
+				return err
+			}
+			break
+		}
+		pending = remaining
+	}
+	return errors.Join(errs...)
+}
+
+// refersTo reports whether a template refers to any of the named fields of its data, such as {{.Host}}
+func refersTo(node parse.Node, fields map[string]bool) bool {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, child := range n.Nodes {
+			if refersTo(child, fields) {
+				return true
+			}
+		}
+	case *parse.ActionNode:
+		return refersTo(n.Pipe, fields)
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for _, cmd := range n.Cmds {
+			if refersTo(cmd, fields) {
+				return true
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if refersTo(arg, fields) {
+				return true
+			}
+		}
+	case *parse.ChainNode:
+		return refersTo(n.Node, fields)
+	case *parse.FieldNode:
+		return fields[n.Ident[0]]
+	case *parse.IfNode:
+		return refersTo(n.Pipe, fields) || refersTo(n.List, fields) || refersTo(n.ElseList, fields)
+	case *parse.RangeNode:
+		return refersTo(n.Pipe, fields) || refersTo(n.List, fields) || refersTo(n.ElseList, fields)
+	case *parse.WithNode:
+		return refersTo(n.Pipe, fields) || refersTo(n.List, fields) || refersTo(n.ElseList, fields)
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package ectoenv
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBindEnvTemplateDefault(t *testing.T) {
+	type Config struct {
+		URL     string        `env:"URL" env-default:"http://{{.Addr}}/{{.Path}}" env-default-template:"true"`
+		Addr    string        `env:"ADDR" env-default:"{{.Host}}:{{.Port}}" env-default-template:"true"`
+		Host    string        `env:"HOST" env-default:"localhost"`
+		Port    int           `env:"PORT" env-default:"8080"`
+		Path    string        `env:"PATH_PREFIX" env-default:"api"`
+		Timeout time.Duration `env:"TIMEOUT" env-default:"{{.Port}}ms" env-default-template:"true"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected Config
+	}{
+		{
+			name:     "Defaults",
+			envVars:  map[string]string{},
+			expected: Config{URL: "http://localhost:8080/api", Addr: "localhost:8080", Host: "localhost", Port: 8080, Path: "api", Timeout: 8080 * time.Millisecond},
+		},
+		{
+			name:     "Siblings set",
+			envVars:  map[string]string{"HOST": "example.com", "PORT": "443"},
+			expected: Config{URL: "http://example.com:443/api", Addr: "example.com:443", Host: "example.com", Port: 443, Path: "api", Timeout: 443 * time.Millisecond},
+		},
+		{
+			name:     "Variable set",
+			envVars:  map[string]string{"ADDR": "10.0.0.1:80"},
+			expected: Config{URL: "http://10.0.0.1:80/api", Addr: "10.0.0.1:80", Host: "localhost", Port: 8080, Path: "api", Timeout: 8080 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			if err := BindEnvFromMap(&config, tt.envVars); err != nil {
+				t.Fatalf("BindEnvFromMap() error = %v", err)
+			}
+			if config != tt.expected {
+				t.Errorf("BindEnvFromMap() got = %+v, want %+v", config, tt.expected)
+			}
+		})
+	}
+
+	var config Config
+	if err := ResetDefaults(&config); err != nil {
+		t.Fatalf("ResetDefaults() error = %v", err)
+	}
+	if config.URL != "http://localhost:8080/api" {
+		t.Errorf("ResetDefaults() got URL = %s", config.URL)
+	}
+}
+
+func TestBindEnvTemplateDefaultErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  interface{}
+		wantErr string
+	}{
+		{
+			name: "Cycle",
+			config: &struct {
+				A string `env:"A" env-default:"{{.B}}" env-default-template:"true"`
+				B string `env:"B" env-default:"{{.A}}" env-default-template:"true"`
+			}{},
+			wantErr: "unable to set value for fields A, B. default templates refer to each other",
+		},
+		{
+			name: "Syntax",
+			config: &struct {
+				A string `env:"A" env-default:"{{.B" env-default-template:"true"`
+			}{},
+			wantErr: "unable to set value for field A. invalid default template",
+		},
+		{
+			name: "Unknown field",
+			config: &struct {
+				A string `env:"A" env-default:"{{.Missing}}" env-default-template:"true"`
+			}{},
+			wantErr: "unable to set value for field A. failed to render default template",
+		},
+		{
+			name: "Invalid result",
+			config: &struct {
+				A int    `env:"A" env-default:"{{.B}}" env-default-template:"true"`
+				B string `env:"B" env-default:"many"`
+			}{},
+			wantErr: "unable to set value for field A. failed to parse many as int",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := BindEnvFromMap(tt.config, map[string]string{})
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBindEnvTemplateDefaultCollect(t *testing.T) {
+	type Config struct {
+		A    string `env:"A" env-default:"{{.Missing}}" env-default-template:"true"`
+		B    int    `env:"B" env-default:"{{.Host}}" env-default-template:"true"`
+		C    string `env:"C" env-default:"{{.D" env-default-template:"true"`
+		Addr string `env:"ADDR" env-default:"{{.Host}}:80" env-default-template:"true"`
+		Host string `env:"HOST" env-default:"localhost"`
+	}
+
+	var config Config
+	err := BindEnvWith(&config, withLookupMap(map[string]string{}), WithErrorMode(Collect))
+	if err == nil {
+		t.Fatalf("BindEnvWith() expected an error")
+	}
+	for _, want := range []string{
+		"unable to set value for field A. failed to render default template",
+		"unable to set value for field B. failed to parse localhost as int",
+		"unable to set value for field C. invalid default template",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("BindEnvWith() error = %v, want it to contain %s", err, want)
+		}
+	}
+	if config.Addr != "localhost:80" {
+		t.Errorf("BindEnvWith() got Addr = %q, want localhost:80", config.Addr)
+	}
+}
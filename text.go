@@ -0,0 +1,33 @@
+package ectoenv
+
+import (
+	"encoding"
+	"reflect"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// isTextUnmarshaler reports whether a pointer to t implements encoding.TextUnmarshaler, as types such as netip.Addr,
+// netip.Prefix and net.IP do
+func isTextUnmarshaler(t reflect.Type) bool {
+	return t.Kind() != reflect.Ptr && reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// isNetTextType reports whether t is a text type of the net or net/netip packages, such as netip.Prefix or net.IP, whose
+// text forms never contain surrounding spaces
+func isNetTextType(t reflect.Type) bool {
+	return isTextUnmarshaler(t) && (t.PkgPath() == "net" || t.PkgPath() == "net/netip")
+}
+
+// setTextField sets a field whose pointer implements encoding.TextUnmarshaler by calling UnmarshalText on a new value
+// that is then stored in the field, so that the field does not need to be addressable, as the elements of a map are not
+func setTextField(field reflect.Value, name string, envValue string) error {
+	val := reflect.New(field.Type())
+	if err := val.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(envValue)); err != nil {
+		return &ParseError{Name: name, Value: envValue, Kind: field.Type().String(), Err: err}
+	}
+	field.Set(val.Elem())
+	return nil
+}
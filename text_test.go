@@ -0,0 +1,245 @@
+package ectoenv
+
+import (
+	"math/big"
+	"net"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBindEnvTextUnmarshaler(t *testing.T) {
+	type Config struct {
+		Addr     netip.Addr     `env:"ADDR"`
+		LinkAddr netip.Addr     `env:"LINK_ADDR"`
+		Subnet   netip.Prefix   `env:"SUBNET"`
+		Listen   netip.AddrPort `env:"LISTEN"`
+		Peers    []netip.Addr   `env:"PEERS"`
+		Gateway  *netip.Addr    `env:"GATEWAY"`
+		IP       net.IP         `env:"IP"`
+	}
+
+	var config Config
+	err := BindEnvFromMap(&config, map[string]string{
+		"ADDR":      "192.0.2.1",
+		"LINK_ADDR": "fe80::1%eth0",
+		"SUBNET":    "2001:db8::/32",
+		"LISTEN":    "[::1]:8080",
+		"PEERS":     "10.0.0.1,10.0.0.2",
+		"GATEWAY":   "10.0.0.254",
+		"IP":        "198.51.100.7",
+	})
+	if err != nil {
+		t.Fatalf("BindEnvFromMap() error = %v", err)
+	}
+
+	gateway := netip.MustParseAddr("10.0.0.254")
+	expected := Config{
+		Addr:     netip.MustParseAddr("192.0.2.1"),
+		LinkAddr: netip.MustParseAddr("fe80::1%eth0"),
+		Subnet:   netip.MustParsePrefix("2001:db8::/32"),
+		Listen:   netip.MustParseAddrPort("[::1]:8080"),
+		Peers:    []netip.Addr{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("10.0.0.2")},
+		Gateway:  &gateway,
+		IP:       net.ParseIP("198.51.100.7"),
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvFromMap() got = %v, want %v", config, expected)
+	}
+	if config.LinkAddr.Zone() != "eth0" {
+		t.Errorf("BindEnvFromMap() zone got = %s, want eth0", config.LinkAddr.Zone())
+	}
+
+	err = BindEnvFromMap(&config, map[string]string{"SUBNET": "10.0.0.0/33"})
+	if err == nil {
+		t.Errorf("BindEnvFromMap() expected error for an invalid prefix, got nil")
+	}
+}
+
+func TestBindEnvPrefixSlice(t *testing.T) {
+	type Config struct {
+		Allowlist []netip.Prefix  `env:"ALLOWLIST"`
+		Optional  []*netip.Prefix `env:"OPTIONAL"`
+	}
+
+	v4 := netip.MustParsePrefix("10.0.0.0/8")
+	v6 := netip.MustParsePrefix("2001:db8::/32")
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected Config
+		wantErr  string
+	}{
+		{
+			name:     "IPv4 and IPv6",
+			envVars:  map[string]string{"ALLOWLIST": "10.0.0.0/8,2001:db8::/32,192.168.1.0/24"},
+			expected: Config{Allowlist: []netip.Prefix{v4, v6, netip.MustParsePrefix("192.168.1.0/24")}},
+		},
+		{
+			name:     "Spaces after commas",
+			envVars:  map[string]string{"ALLOWLIST": "10.0.0.0/8, 2001:db8::/32", "OPTIONAL": " 10.0.0.0/8 "},
+			expected: Config{Allowlist: []netip.Prefix{v4, v6}, Optional: []*netip.Prefix{&v4}},
+		},
+		{
+			name:     "JSON array",
+			envVars:  map[string]string{"ALLOWLIST": `["10.0.0.0/8", "2001:db8::/32"]`},
+			expected: Config{Allowlist: []netip.Prefix{v4, v6}},
+		},
+		{
+			name:    "Prefix length out of range",
+			envVars: map[string]string{"ALLOWLIST": "10.0.0.0/8,10.0.0.0/33"},
+			wantErr: `unable to set value for field Allowlist[1]. failed to parse 10.0.0.0/33 as netip.Prefix: netip.ParsePrefix("10.0.0.0/33"): prefix length out of range`,
+		},
+		{
+			name:    "Missing length",
+			envVars: map[string]string{"ALLOWLIST": "2001:db8::"},
+			wantErr: `unable to set value for field Allowlist[0]. failed to parse 2001:db8:: as netip.Prefix: netip.ParsePrefix("2001:db8::"): no '/'`,
+		},
+		{
+			name:    "Invalid JSON element",
+			envVars: map[string]string{"ALLOWLIST": `["10.0.0.0/8", "10.0.0.300/8"]`},
+			wantErr: `unable to set value for field Allowlist[1]. failed to parse 10.0.0.300/8 as netip.Prefix: netip.ParsePrefix("10.0.0.300/8"): ParseAddr("10.0.0.300"): IPv4 field has value >255`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvFromMap(&config, tt.envVars)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("BindEnvFromMap() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvFromMap() error = %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("BindEnvFromMap() got = %v, want %v", config, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvTextUnmarshalerMap(t *testing.T) {
+	type Config struct {
+		Routes   map[netip.Prefix]netip.Addr `env:"ROUTES"`
+		Backends map[string][]netip.AddrPort `env:"BACKENDS"`
+		Levels   map[string]testLevel        `env:"LEVELS"`
+		Optional map[string]*netip.Addr      `env:"OPTIONAL"`
+		Lists    map[testLevel]testList      `env:"LISTS"`
+	}
+
+	var config Config
+	err := BindEnvFromMap(&config, map[string]string{
+		"ROUTES":   "10.0.0.0/8=10.0.0.1,::/0=fe80::1%eth0",
+		"BACKENDS": "api=10.0.0.1:80|10.0.0.2:80",
+		"LEVELS":   "db=debug,http=error",
+		"OPTIONAL": "dns=1.1.1.1",
+		"LISTS":    "info=a",
+	})
+	if err != nil {
+		t.Fatalf("BindEnvFromMap() error = %v", err)
+	}
+
+	dns := netip.MustParseAddr("1.1.1.1")
+	expected := Config{
+		Routes: map[netip.Prefix]netip.Addr{
+			netip.MustParsePrefix("10.0.0.0/8"): netip.MustParseAddr("10.0.0.1"),
+			netip.MustParsePrefix("::/0"):       netip.MustParseAddr("fe80::1%eth0"),
+		},
+		Backends: map[string][]netip.AddrPort{
+			"api": {netip.MustParseAddrPort("10.0.0.1:80"), netip.MustParseAddrPort("10.0.0.2:80")},
+		},
+		Levels:   map[string]testLevel{"db": 0, "http": 2},
+		Optional: map[string]*netip.Addr{"dns": &dns},
+		Lists:    map[testLevel]testList{1: {"a"}},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvFromMap() got = %v, want %v", config, expected)
+	}
+}
+
+// testDecimal is a fixed point decimal that parses its text exactly, like the decimal types used for money
+type testDecimal struct {
+	units int64
+	scale int
+}
+
+func (d *testDecimal) UnmarshalText(text []byte) error {
+	whole, frac, _ := strings.Cut(string(text), ".")
+	units, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return err
+	}
+	*d = testDecimal{units: units, scale: len(frac)}
+	return nil
+}
+
+func TestBindEnvDecimal(t *testing.T) {
+	type Config struct {
+		Price    testDecimal   `env:"PRICE"`
+		Tiers    []testDecimal `env:"TIERS"`
+		TaxRate  *big.Rat      `env:"TAX_RATE"`
+		Discount big.Rat       `env:"DISCOUNT"`
+	}
+
+	var config Config
+	err := BindEnvFromMap(&config, map[string]string{
+		"PRICE":    "19.99",
+		"TIERS":    "0.10,0.30",
+		"TAX_RATE": "0.0825",
+		"DISCOUNT": "1/3",
+	})
+	if err != nil {
+		t.Fatalf("BindEnvFromMap() error = %v", err)
+	}
+
+	if config.Price != (testDecimal{units: 1999, scale: 2}) {
+		t.Errorf("BindEnvFromMap() Price got = %v, want 1999e-2", config.Price)
+	}
+	expectedTiers := []testDecimal{{units: 10, scale: 2}, {units: 30, scale: 2}}
+	if !reflect.DeepEqual(config.Tiers, expectedTiers) {
+		t.Errorf("BindEnvFromMap() Tiers got = %v, want %v", config.Tiers, expectedTiers)
+	}
+	if config.TaxRate.Cmp(big.NewRat(825, 10000)) != 0 {
+		t.Errorf("BindEnvFromMap() TaxRate got = %v, want 825/10000", config.TaxRate)
+	}
+	if config.Discount.Cmp(big.NewRat(1, 3)) != 0 {
+		t.Errorf("BindEnvFromMap() Discount got = %v, want 1/3", &config.Discount)
+	}
+
+	err = BindEnvFromMap(&config, map[string]string{"PRICE": "19.99.1"})
+	if err == nil {
+		t.Errorf("BindEnvFromMap() expected error for an invalid decimal, got nil")
+	}
+}
+
+// testLabel is a text type whose text may contain meaningful spaces
+type testLabel struct {
+	text string
+}
+
+func (l *testLabel) UnmarshalText(text []byte) error {
+	l.text = string(text)
+	return nil
+}
+
+func TestBindEnvTextSliceKeepsSpaces(t *testing.T) {
+	type Config struct {
+		Labels []testLabel `env:"LABELS"`
+	}
+
+	var config Config
+	if err := BindEnvFromMap(&config, map[string]string{"LABELS": " padded , plain"}); err != nil {
+		t.Fatalf("BindEnvFromMap() error = %v", err)
+	}
+
+	expected := Config{Labels: []testLabel{{text: " padded "}, {text: " plain"}}}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnvFromMap() got = %+v, want %+v", config, expected)
+	}
+}
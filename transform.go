@@ -0,0 +1,46 @@
+package ectoenv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	transformersMu sync.RWMutex
+	transformers   = map[string]func(string) string{
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+		"trim":  strings.TrimSpace,
+	}
+)
+
+// RegisterTransformer registers a transformer that can be referenced by name in the `env-transform` tag. Transformers
+// are applied to the raw environment value before it is converted to the field's type. Registering a transformer with
+// the name of an existing transformer replaces it.
+// name: the name used to reference the transformer in the tag
+// fn: the function used to transform the value
+func RegisterTransformer(name string, fn func(string) string) {
+	transformersMu.Lock()
+	defer transformersMu.Unlock()
+	transformers[name] = fn
+}
+
+func applyTransformers(field reflect.StructField, envValue string) (string, error) {
+	transformTag := getTag(field, ENV_TRANSFORM_TAG)
+	if transformTag == "" {
+		return envValue, nil
+	}
+
+	transformersMu.RLock()
+	defer transformersMu.RUnlock()
+	for _, name := range strings.Split(transformTag, ",") {
+		fn, ok := transformers[name]
+		if !ok {
+			return "", fmt.Errorf("unable to set value for field %s. unknown transformer %s", field.Name, name)
+		}
+		envValue = fn(envValue)
+	}
+	return envValue, nil
+}
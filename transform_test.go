@@ -0,0 +1,58 @@
+package ectoenv
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBindEnvTransform(t *testing.T) {
+	RegisterTransformer("trimslash", func(s string) string {
+		return strings.TrimSuffix(s, "/")
+	})
+
+	type Config struct {
+		Region string `env:"TEST_TRANSFORM_REGION" env-transform:"trim,lower"`
+		URL    string `env:"TEST_TRANSFORM_URL" env-transform:"trimslash"`
+		Level  string `env:"TEST_TRANSFORM_LEVEL" env-transform:"lower" env-oneof:"debug,info"`
+	}
+
+	envVars := map[string]string{
+		"TEST_TRANSFORM_REGION": " US-East-1 ",
+		"TEST_TRANSFORM_URL":    "https://example.com/",
+		"TEST_TRANSFORM_LEVEL":  "DEBUG",
+	}
+	for k, v := range envVars {
+		os.Setenv(k, v)
+	}
+	defer func() {
+		for k := range envVars {
+			os.Unsetenv(k)
+		}
+	}()
+
+	var config Config
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+
+	expected := Config{Region: "us-east-1", URL: "https://example.com", Level: "debug"}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("BindEnv() got = %v, want %v", config, expected)
+	}
+}
+
+func TestBindEnvUnknownTransform(t *testing.T) {
+	type Config struct {
+		Value string `env:"TEST_TRANSFORM_UNKNOWN" env-transform:"missing"`
+	}
+
+	os.Setenv("TEST_TRANSFORM_UNKNOWN", "value")
+	defer os.Unsetenv("TEST_TRANSFORM_UNKNOWN")
+
+	var config Config
+	if err := BindEnv(&config); err == nil {
+		t.Errorf("BindEnv() expected error for unknown transformer, got nil")
+	}
+}
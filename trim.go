@@ -0,0 +1,34 @@
+package ectoenv
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ENV_TRIM_PREFIX_TAG is the tag used to remove a prefix from the value before it is converted, such as "Bearer " from a
+// token. A value without the prefix is left as it is.
+var ENV_TRIM_PREFIX_TAG = "env-trim-prefix"
+
+// ENV_TRIM_SUFFIX_TAG is the tag used to remove a suffix from the value before it is converted, such as the trailing "/"
+// of a URL. A value without the suffix is left as it is.
+var ENV_TRIM_SUFFIX_TAG = "env-trim-suffix"
+
+// hasAffixTrim reports whether the field removes a prefix or a suffix from its values
+func hasAffixTrim(field reflect.StructField) bool {
+	return getTag(field, ENV_TRIM_PREFIX_TAG) != "" || getTag(field, ENV_TRIM_SUFFIX_TAG) != ""
+}
+
+// trimAffixes removes the prefix and suffix set with `env-trim-prefix` and `env-trim-suffix` from the value, once each
+func trimAffixes(field reflect.StructField, value string) string {
+	value = strings.TrimPrefix(value, getTag(field, ENV_TRIM_PREFIX_TAG))
+	return strings.TrimSuffix(value, getTag(field, ENV_TRIM_SUFFIX_TAG))
+}
+
+// trimsWholeValue reports whether the affixes are removed from the value as a whole, which is the case for every type but
+// slices, whose elements are trimmed, and maps, whose values are trimmed. A []byte is decoded from the value as a whole.
+func trimsWholeValue(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t == bytesType || (t.Kind() != reflect.Slice && t.Kind() != reflect.Map)
+}
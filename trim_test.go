@@ -0,0 +1,80 @@
+package ectoenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBindEnvTrimAffixes(t *testing.T) {
+	type Config struct {
+		Token     string              `env:"TOKEN" env-trim-prefix:"Bearer "`
+		BaseURL   string              `env:"BASE_URL" env-trim-suffix:"/"`
+		Timeout   *int                `env:"TIMEOUT" env-trim-suffix:"s"`
+		Endpoints []string            `env:"ENDPOINTS" env-trim-prefix:"https://" env-trim-suffix:"/"`
+		Ports     []int               `env:"PORTS" env-trim-prefix:":"`
+		Level     string              `env:"LEVEL" env-trim-prefix:"log-" env-oneof:"debug,info"`
+		Upstreams map[string]string   `env:"UPSTREAMS" env-trim-suffix:"/"`
+		Routes    map[string][]string `env:"ROUTES" env-trim-suffix:"/"`
+	}
+
+	timeout := 30
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Config
+		wantErr  bool
+	}{
+		{
+			name: "affixes present",
+			env: map[string]string{
+				"TOKEN":     "Bearer abc123",
+				"BASE_URL":  "https://api.example.com/",
+				"TIMEOUT":   "30s",
+				"ENDPOINTS": "https://a.example.com/,b.example.com",
+				"PORTS":     ":8080,:9090",
+				"LEVEL":     "log-debug",
+				"UPSTREAMS": "api=http://api/,web=http://web",
+				"ROUTES":    "api=/v1/|/v2/",
+			},
+			expected: Config{
+				Token:     "abc123",
+				BaseURL:   "https://api.example.com",
+				Timeout:   &timeout,
+				Endpoints: []string{"a.example.com", "b.example.com"},
+				Ports:     []int{8080, 9090},
+				Level:     "debug",
+				Upstreams: map[string]string{"api": "http://api", "web": "http://web"},
+				Routes:    map[string][]string{"api": {"/v1", "/v2"}},
+			},
+		},
+		{
+			name:     "affixes absent",
+			env:      map[string]string{"TOKEN": "abc123", "BASE_URL": "https://api.example.com", "LEVEL": "info"},
+			expected: Config{Token: "abc123", BaseURL: "https://api.example.com", Level: "info"},
+		},
+		{
+			name:     "json array",
+			env:      map[string]string{"ENDPOINTS": `["https://a.example.com/", "https://b.example.com/"]`},
+			expected: Config{Endpoints: []string{"a.example.com", "b.example.com"}},
+		},
+		{
+			name:    "trimmed once",
+			env:     map[string]string{"LEVEL": "log-log-debug"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvWith(&config, withLookupMap(tt.env))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BindEnvWith() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("BindEnvWith() got = %+v, want %+v", config, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package ectoenv
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var byteUnits = map[string]float64{
+	"":    1,
+	"B":   1,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"PB":  1e15,
+	"KIB": 1 << 10,
+	"MIB": 1 << 20,
+	"GIB": 1 << 30,
+	"TIB": 1 << 40,
+	"PIB": 1 << 50,
+}
+
+func setUnitField(field reflect.Value, name string, unit string, envValue string) error {
+	switch unit {
+	case "bytes":
+		val, err := parseByteSize(envValue)
+		if err != nil {
+			return &ParseError{Name: name, Value: envValue, Kind: "byte size", Err: err}
+		}
+
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if field.OverflowInt(val) {
+				return &ParseError{Name: name, Value: envValue, Kind: "byte size", Err: fmt.Errorf("%d bytes overflows %s: %w", val, field.Kind(), strconv.ErrRange)}
+			}
+			field.SetInt(val)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if field.OverflowUint(uint64(val)) {
+				return &ParseError{Name: name, Value: envValue, Kind: "byte size", Err: fmt.Errorf("%d bytes overflows %s: %w", val, field.Kind(), strconv.ErrRange)}
+			}
+			field.SetUint(uint64(val))
+		default:
+			return fmt.Errorf("unable to set value for field %s. unit %s is not supported for %s fields", name, unit, field.Kind())
+		}
+		return nil
+	case "percent":
+		if field.Kind() != reflect.Float64 {
+			return fmt.Errorf("unable to set value for field %s. unit %s is not supported for %s fields", name, unit, field.Kind())
+		}
+		val, err := parsePercent(envValue)
+		if err != nil {
+			return &ParseError{Name: name, Value: envValue, Kind: "percent", Err: err}
+		}
+		field.SetFloat(val)
+		return nil
+	}
+	return fmt.Errorf("unable to set value for field %s. unknown unit %s", name, unit)
+}
+
+// parseByteSize parses a human-readable size such as "10MB" or "1.5GiB" into a number of bytes. Decimal units (KB, MB,
+// ...) are powers of 1000 and binary units (KiB, MiB, ...) are powers of 1024. A value without a unit is in bytes.
+func parseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i == -1 {
+		i = len(s)
+	}
+
+	num, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, err
+	}
+
+	unit := strings.ToUpper(strings.TrimSpace(s[i:]))
+	multiplier, ok := byteUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", s[i:])
+	}
+
+	// math.MaxInt64 rounds up to 2^63 as a float64, which no longer fits in an int64
+	size := num * multiplier
+	if size >= math.Exp2(63) {
+		return 0, strconv.ErrRange
+	}
+	return int64(size), nil
+}
+
+// parsePercent parses a percentage such as "25%" into a fraction, e.g. 0.25
+func parsePercent(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasSuffix(s, "%") {
+		return 0, fmt.Errorf("missing %% suffix")
+	}
+
+	val, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(s, "%")), 64)
+	if err != nil {
+		return 0, err
+	}
+	return val / 100, nil
+}
@@ -0,0 +1,177 @@
+package ectoenv
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{input: "512", expected: 512},
+		{input: "512B", expected: 512},
+		{input: "10KB", expected: 10000},
+		{input: "10MB", expected: 10000000},
+		{input: "10 mb", expected: 10000000},
+		{input: "1GB", expected: 1000000000},
+		{input: "10KiB", expected: 10240},
+		{input: "10MiB", expected: 10485760},
+		{input: "1.5GiB", expected: 1610612736},
+		{input: "10XB", wantErr: true},
+		{input: "MB", wantErr: true},
+		{input: "8191PiB", expected: 8191 << 50},
+		{input: "8192PiB", wantErr: true},
+		{input: "9223372036854775808", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseByteSize(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseByteSize() expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseByteSize() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("parseByteSize() got = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvByteUnit(t *testing.T) {
+	type Config struct {
+		MaxUpload int `env:"TEST_UNIT_MAX_UPLOAD" env-unit:"bytes"`
+	}
+
+	os.Setenv("TEST_UNIT_MAX_UPLOAD", "10MiB")
+	defer os.Unsetenv("TEST_UNIT_MAX_UPLOAD")
+
+	var config Config
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+	if config.MaxUpload != 10<<20 {
+		t.Errorf("BindEnv() got = %v, want %v", config.MaxUpload, 10<<20)
+	}
+
+	os.Setenv("TEST_UNIT_MAX_UPLOAD", "10 parsecs")
+	if err := BindEnv(&config); err == nil {
+		t.Errorf("BindEnv() expected error for unrecognized unit, got nil")
+	}
+}
+
+func TestBindEnvByteUnitSizedInts(t *testing.T) {
+	type Config struct {
+		Cache  int64  `env:"CACHE" env-unit:"bytes"`
+		Disk   uint64 `env:"DISK" env-unit:"bytes"`
+		Buffer int32  `env:"BUFFER" env-unit:"bytes"`
+		Chunk  uint16 `env:"CHUNK" env-unit:"bytes"`
+	}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Config
+		wantErr  bool
+	}{
+		{
+			name:     "in range",
+			env:      map[string]string{"CACHE": "512MiB", "DISK": "2TB", "BUFFER": "1GiB", "CHUNK": "64KB"},
+			expected: Config{Cache: 512 << 20, Disk: 2e12, Buffer: 1 << 30, Chunk: 64000},
+		},
+		{
+			name:    "int32 overflow",
+			env:     map[string]string{"BUFFER": "4GiB"},
+			wantErr: true,
+		},
+		{
+			name:    "uint16 overflow",
+			env:     map[string]string{"CHUNK": "1MB"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvWith(&config, withLookupMap(tt.env))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BindEnvWith() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && config != tt.expected {
+				t.Errorf("BindEnvWith() got = %+v, want %+v", config, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBindEnvPercentUnit(t *testing.T) {
+	type Config struct {
+		SampleRate float64 `env:"TEST_UNIT_SAMPLE_RATE" env-unit:"percent"`
+		Plain      float64 `env:"TEST_UNIT_PLAIN"`
+	}
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected Config
+		wantErr  bool
+	}{
+		{
+			name:     "Percent",
+			envVars:  map[string]string{"TEST_UNIT_SAMPLE_RATE": "25%", "TEST_UNIT_PLAIN": "25"},
+			expected: Config{SampleRate: 0.25, Plain: 25},
+		},
+		{
+			name:     "Fractional percent",
+			envVars:  map[string]string{"TEST_UNIT_SAMPLE_RATE": "12.5%"},
+			expected: Config{SampleRate: 0.125},
+		},
+		{
+			name:    "Missing suffix",
+			envVars: map[string]string{"TEST_UNIT_SAMPLE_RATE": "25"},
+			wantErr: true,
+		},
+		{
+			name:    "Malformed",
+			envVars: map[string]string{"TEST_UNIT_SAMPLE_RATE": "abc%"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.envVars {
+				os.Setenv(k, v)
+			}
+			defer func() {
+				for k := range tt.envVars {
+					os.Unsetenv(k)
+				}
+			}()
+
+			var config Config
+			err := BindEnv(&config)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("BindEnv() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnv() error = %v", err)
+			}
+			if config != tt.expected {
+				t.Errorf("BindEnv() got = %v, want %v", config, tt.expected)
+			}
+		})
+	}
+}
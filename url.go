@@ -0,0 +1,27 @@
+package ectoenv
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+var urlType = reflect.TypeOf(url.URL{})
+
+// setURLField parses the value into a url.URL field. A URL never contains surrounding spaces, so they are trimmed, and
+// an empty value is rejected rather than bound as an empty URL, so that a list such as "a,,b" does not yield an
+// element without a URL.
+func setURLField(field reflect.Value, name string, envValue string) error {
+	envValue = strings.TrimSpace(envValue)
+	if envValue == "" {
+		return &ParseError{Name: name, Value: envValue, Kind: "url", Err: errors.New("empty URL")}
+	}
+
+	u, err := url.Parse(envValue)
+	if err != nil {
+		return &ParseError{Name: name, Value: envValue, Kind: "url", Err: err}
+	}
+	field.Set(reflect.ValueOf(*u))
+	return nil
+}
@@ -0,0 +1,105 @@
+package ectoenv
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestBindEnvURLs(t *testing.T) {
+	type Config struct {
+		Endpoint  url.URL    `env:"ENDPOINT"`
+		Upstreams []url.URL  `env:"UPSTREAMS"`
+		Mirrors   []*url.URL `env:"MIRRORS" env-skip-empty:"true"`
+	}
+
+	tests := []struct {
+		name      string
+		env       map[string]string
+		upstreams []string
+		mirrors   []string
+		wantErr   string
+		wantName  string
+		wantValue string
+	}{
+		{
+			name: "list",
+			env: map[string]string{
+				"ENDPOINT":  "https://api.example.com/v1",
+				"UPSTREAMS": "http://10.0.0.1:8080, http://10.0.0.2:8080/health?full=1",
+				"MIRRORS":   "https://a.example.com,,https://b.example.com,",
+			},
+			upstreams: []string{"http://10.0.0.1:8080", "http://10.0.0.2:8080/health?full=1"},
+			mirrors:   []string{"https://a.example.com", "https://b.example.com"},
+		},
+		{
+			name:      "json array",
+			env:       map[string]string{"UPSTREAMS": `["http://a:1", "http://b:2"]`},
+			upstreams: []string{"http://a:1", "http://b:2"},
+		},
+		{
+			name:      "malformed element",
+			env:       map[string]string{"UPSTREAMS": "http://a:1,http://[::1"},
+			wantErr:   "unable to set value for field Upstreams[1]",
+			wantName:  "Upstreams[1]",
+			wantValue: "http://[::1",
+		},
+		{
+			name:      "empty element",
+			env:       map[string]string{"UPSTREAMS": "http://a:1,,http://b:2"},
+			wantErr:   "unable to set value for field Upstreams[1]",
+			wantName:  "Upstreams[1]",
+			wantValue: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config Config
+			err := BindEnvWith(&config, withLookupMap(tt.env))
+			if tt.wantErr != "" {
+				var parseErr *ParseError
+				if !errors.As(err, &parseErr) {
+					t.Fatalf("BindEnvWith() error = %v, want *ParseError", err)
+				}
+				if parseErr.Name != tt.wantName || parseErr.Value != tt.wantValue || parseErr.Kind != "url" {
+					t.Errorf("ParseError got = %+v, want Name=%s Value=%q Kind=url", parseErr, tt.wantName, tt.wantValue)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BindEnvWith() error = %v", err)
+			}
+
+			if endpoint := tt.env["ENDPOINT"]; endpoint != "" && config.Endpoint.String() != endpoint {
+				t.Errorf("Endpoint got = %s, want %s", config.Endpoint.String(), endpoint)
+			}
+			if len(config.Upstreams) != len(tt.upstreams) {
+				t.Fatalf("Upstreams got = %v, want %v", config.Upstreams, tt.upstreams)
+			}
+			for i, u := range config.Upstreams {
+				if u.String() != tt.upstreams[i] {
+					t.Errorf("Upstreams[%d] got = %s, want %s", i, u.String(), tt.upstreams[i])
+				}
+			}
+			if len(config.Mirrors) != len(tt.mirrors) {
+				t.Fatalf("Mirrors got = %v, want %v", config.Mirrors, tt.mirrors)
+			}
+			for i, u := range config.Mirrors {
+				if u == nil || u.String() != tt.mirrors[i] {
+					t.Errorf("Mirrors[%d] got = %v, want %s", i, u, tt.mirrors[i])
+				}
+			}
+		})
+	}
+
+	config := Config{Upstreams: []url.URL{{Scheme: "http", Host: "a:1"}, {Scheme: "https", Host: "b", Path: "/x"}}}
+	data, err := MarshalEnv(&config)
+	if err != nil {
+		t.Fatalf("MarshalEnv() error = %v", err)
+	}
+	if want := "UPSTREAMS=http://a:1,https://b/x\n"; !strings.Contains(string(data), want) {
+		t.Errorf("MarshalEnv() got = %s, want a line %s", data, want)
+	}
+}
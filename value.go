@@ -0,0 +1,66 @@
+package ectoenv
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// Value holds a value of type T that can be loaded and stored concurrently without a lock. A Value field is bound like a
+// field of type T, but the parsed value is stored atomically, so readers can call Load while BindEnvWithAutoRefresh
+// rebinds the struct. The zero Value is ready to use and loads the zero value of T.
+type Value[T any] struct {
+	v atomic.Value
+}
+
+// valueBox wraps stored values so that atomic.Value always sees the same concrete type, even when T is an interface
+type valueBox[T any] struct {
+	val T
+}
+
+// Load returns the most recently stored value, or the zero value of T if no value has been stored
+func (v *Value[T]) Load() T {
+	box, _ := v.v.Load().(valueBox[T])
+	return box.val
+}
+
+// Store atomically replaces the value
+func (v *Value[T]) Store(val T) {
+	v.v.Store(valueBox[T]{val: val})
+}
+
+// valueType returns the type of the values held by the Value
+func (v *Value[T]) valueType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// storeValue stores a reflected value, which must be of the type returned by valueType
+func (v *Value[T]) storeValue(val reflect.Value) {
+	v.Store(val.Interface().(T))
+}
+
+// valueStorer is implemented by pointers to Value so that binding can detect Value fields regardless of T
+type valueStorer interface {
+	valueType() reflect.Type
+	storeValue(val reflect.Value)
+}
+
+var valueStorerType = reflect.TypeOf((*valueStorer)(nil)).Elem()
+
+// isValueType reports whether t is an instantiation of Value
+func isValueType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(valueStorerType)
+}
+
+// setValueField parses the value as the type held by the Value field and stores it atomically. An empty value stores the
+// zero value.
+func setValueField(field reflect.Value, structField reflect.StructField, opts parseOptions, envValue string) error {
+	storer := field.Addr().Interface().(valueStorer)
+	val := reflect.New(storer.valueType()).Elem()
+	if envValue != "" {
+		if err := setFieldValue(val, structField, opts, envValue); err != nil {
+			return err
+		}
+	}
+	storer.storeValue(val)
+	return nil
+}
@@ -0,0 +1,70 @@
+package ectoenv
+
+import (
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestValue(t *testing.T) {
+	var v Value[error]
+	if got := v.Load(); got != nil {
+		t.Errorf("Load() on zero Value got = %v, want nil", got)
+	}
+
+	var s Value[[]string]
+	s.Store([]string{"a"})
+	if got := s.Load(); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("Load() got = %v, want %v", got, []string{"a"})
+	}
+}
+
+func TestBindEnvValue(t *testing.T) {
+	type Config struct {
+		Timeout Value[int]       `env:"TEST_VALUE_TIMEOUT" env-default:"30"`
+		Hosts   Value[[]string]  `env:"TEST_VALUE_HOSTS"`
+		Start   Value[time.Time] `env:"TEST_VALUE_START" env-layout:"unix"`
+	}
+
+	os.Setenv("TEST_VALUE_HOSTS", "a,b")
+	os.Setenv("TEST_VALUE_START", "1700000000")
+	defer os.Unsetenv("TEST_VALUE_HOSTS")
+	defer os.Unsetenv("TEST_VALUE_START")
+
+	var config Config
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+
+	if got := config.Timeout.Load(); got != 30 {
+		t.Errorf("Timeout got = %v, want %v", got, 30)
+	}
+	if got := config.Hosts.Load(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Hosts got = %v, want %v", got, []string{"a", "b"})
+	}
+	if got := config.Start.Load(); !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Start got = %v, want %v", got, time.Unix(1700000000, 0))
+	}
+
+	// readers can load concurrently with a rebind
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = config.Timeout.Load()
+		}
+	}()
+	os.Setenv("TEST_VALUE_TIMEOUT", "60")
+	defer os.Unsetenv("TEST_VALUE_TIMEOUT")
+	if err := BindEnv(&config); err != nil {
+		t.Fatalf("BindEnv() error = %v", err)
+	}
+	wg.Wait()
+
+	if got := config.Timeout.Load(); got != 60 {
+		t.Errorf("Timeout got = %v, want %v", got, 60)
+	}
+}
@@ -0,0 +1,101 @@
+package ectoenv
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// BindEnvWatch binds the provided struct immediately and then rebinds it on the interval set with
+// `AUTO_REFRESH_INTERVAL`, like BindEnvWithAutoRefresh. Each refresh that changes at least one field sends the paths of
+// the changed fields, such as "Database.URL" or "Upstreams[1].URL", on the returned channel. Secrets are compared by
+// their actual values, so rotating one is reported. The channel is closed once ctx is canceled, which also stops the
+// refreshes. Refresh failures are reported as they are by BindEnvWithAutoRefresh.
+// ctx: the context that stops the refreshes when canceled
+// v: a non-nil pointer to a struct
+// opts: the options to apply
+// returns: the channel of changed field paths, or an error if the provided value is not a non-nil pointer to a struct or
+// if the initial bind fails
+func BindEnvWatch(ctx context.Context, v interface{}, opts ...Option) (<-chan []string, error) {
+	rv, err := validateInput(v)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newBinder(opts...)
+	b.trackChanges()
+	if err := b.bind(rv); err != nil {
+		return nil, err
+	}
+
+	changes := make(chan []string)
+	go b.watch(ctx, time.Duration(AUTO_REFRESH_INTERVAL)*time.Second, rv, changes)
+
+	return changes, nil
+}
+
+// watch rebinds rv every interval until ctx is canceled, sending the paths of the fields changed by each refresh
+func (b *binder) watch(ctx context.Context, interval time.Duration, rv reflect.Value, changes chan<- []string) {
+	defer close(changes)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var reporter refreshReporter
+	before := b.collectEnvEntries(rv, "", "", false)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		refreshMu.Lock()
+		err := b.rebind(rv)
+		refreshMu.Unlock()
+		stop := b.reportRefresh(&reporter, err)
+		if b.onRefresh != nil {
+			b.onRefresh()
+		}
+		if stop {
+			return
+		}
+
+		after := b.collectEnvEntries(rv, "", "", false)
+		changed := changedPaths(before, after)
+		before = after
+		if len(changed) == 0 {
+			continue
+		}
+
+		select {
+		case changes <- changed:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// changedPaths returns the paths of the fields whose values differ between two sets of entries in field order, followed
+// by the fields that only exist in before, such as elements removed from a slice of structs
+func changedPaths(before, after []envEntry) []string {
+	old := make(map[string]string, len(before))
+	for _, entry := range before {
+		old[entry.name] = entry.value
+	}
+
+	var paths []string
+	for _, entry := range after {
+		value, ok := old[entry.name]
+		if !ok || value != entry.value {
+			paths = append(paths, entry.name)
+		}
+		delete(old, entry.name)
+	}
+	for _, entry := range before {
+		if _, ok := old[entry.name]; ok {
+			paths = append(paths, entry.name)
+		}
+	}
+	return paths
+}
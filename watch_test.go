@@ -0,0 +1,139 @@
+package ectoenv
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBindEnvWatch(t *testing.T) {
+	type Config struct {
+		Host     string `env:"HOST"`
+		Port     int    `env:"PORT"`
+		Password string `env:"PASSWORD" env-secret:"true"`
+	}
+
+	var mu sync.Mutex
+	envVars := map[string]string{"HOST": "localhost", "PORT": "8080", "PASSWORD": "one"}
+	setEnv := func(key, value string) {
+		mu.Lock()
+		defer mu.Unlock()
+		envVars[key] = value
+	}
+	b := newBinder(WithLookup(func(key string) (string, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		value, ok := envVars[key]
+		return value, ok
+	}))
+
+	var config Config
+	rv := reflect.ValueOf(&config).Elem()
+	if err := b.bind(rv); err != nil {
+		t.Fatalf("bind() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan []string)
+	go b.watch(ctx, 10*time.Millisecond, rv, changes)
+
+	receive := func() []string {
+		t.Helper()
+		select {
+		case changed, ok := <-changes:
+			if !ok {
+				t.Fatalf("BindEnvWatch() channel closed unexpectedly")
+			}
+			return changed
+		case <-time.After(5 * time.Second):
+			t.Fatalf("BindEnvWatch() did not report a change")
+		}
+		return nil
+	}
+
+	setEnv("PORT", "9090")
+	if got, want := receive(), []string{"Port"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("BindEnvWatch() got = %v, want %v", got, want)
+	}
+
+	setEnv("HOST", "example.com")
+	setEnv("PASSWORD", "two")
+	changed := receive()
+	// the two variables may be picked up by different refreshes
+	if len(changed) == 1 && changed[0] == "Host" {
+		changed = append(changed, receive()...)
+	}
+	if want := []string{"Host", "Password"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("BindEnvWatch() got = %v, want %v", changed, want)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-changes:
+		if ok {
+			t.Errorf("BindEnvWatch() sent a change after the context was canceled")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("BindEnvWatch() channel was not closed after the context was canceled")
+	}
+}
+
+func TestBindEnvWatchFlattenedNames(t *testing.T) {
+	type Server struct {
+		Port int
+	}
+	type Config struct {
+		Name   string
+		Server Server
+	}
+
+	var mu sync.Mutex
+	envVars := map[string]string{"NAME": "a", "SERVER__PORT": "8080"}
+	b := newBinder(WithFlattenedNames(""), WithLookup(func(key string) (string, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		value, ok := envVars[key]
+		return value, ok
+	}))
+
+	var config Config
+	rv := reflect.ValueOf(&config).Elem()
+	if err := b.bind(rv); err != nil {
+		t.Fatalf("bind() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan []string)
+	go b.watch(ctx, 10*time.Millisecond, rv, changes)
+
+	mu.Lock()
+	envVars["NAME"] = "b"
+	envVars["SERVER__PORT"] = "9090"
+	mu.Unlock()
+
+	var changed []string
+	for len(changed) < 2 {
+		select {
+		case paths := <-changes:
+			changed = append(changed, paths...)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("BindEnvWatch() did not report a change, got %v", changed)
+		}
+	}
+	if want := []string{"Name", "Server.Port"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("BindEnvWatch() got = %v, want %v", changed, want)
+	}
+}
+
+func TestBindEnvWatchInvalidInput(t *testing.T) {
+	var config struct {
+		Port int `env:"PORT"`
+	}
+	if _, err := BindEnvWatch(context.Background(), config); err == nil {
+		t.Errorf("BindEnvWatch() expected an error for a non-pointer value")
+	}
+}